@@ -1,45 +1,121 @@
 package algo_prog_dynamique
 
 import (
+	"context"
 	"fmt"
-	"math"
 	"time"
 
+	"../algorithme_glouton"
 	"../common"
 )
 
 /* Fonction qui implémente un algorithme de programmation dynamique */
 func Knapsack(objects []common.Objects, capacity_max int) (int, []common.Objects) {
+	value, indices := KnapsackIndices(objects, capacity_max)
+
+	// Récupérer les objets sélectionnés
+	selectedObjects := make([]common.Objects, 0, len(indices))
+	for _, i := range indices {
+		selectedObjects = append(selectedObjects, objects[i])
+	}
+
+	return value, selectedObjects
+}
+
+/* KnapsackIndices renvoie la valeur optimale et les indices des objets sélectionnés (du dernier au premier) */
+func KnapsackIndices(objects []common.Objects, capacity_max int) (int, []int) {
+	value, indices, _ := KnapsackContext(context.Background(), objects, capacity_max)
+	return value, indices
+}
+
+// KnapsackContext est KnapsackIndices interruptible : si le contexte est annulé, la table n'est calculée que
+// pour les premiers objets et la solution renvoyée est optimale pour ceux-là seulement. Retourne aussi le
+// nombre d'objets traités (len(objects) si la table est complète).
+func KnapsackContext(ctx context.Context, objects []common.Objects, capacity_max int) (int, []int, int) {
+	value, indices, dp := knapsackTable(ctx, objects, capacity_max)
+	return value, indices, len(dp) - 1
+}
+
+/* knapsackTable calcule la table ligne par ligne et la renvoie, tronquée aux lignes calculées, avec la solution */
+func knapsackTable(ctx context.Context, objects []common.Objects, capacity_max int) (int, []int, [][]int) {
 	n := len(objects)
 	dp := make([][]int, n+1)
-	for i := 0; i <= n; i++ {
-		dp[i] = make([]int, capacity_max+1)
-	}
+	dp[0] = make([]int, capacity_max+1)
 
-	for i := 0; i <= n; i++ {
+	// La colonne j = 0 n'est pas forcément nulle : des objets de poids nul peuvent y être pris
+	progress := common.NewReporter(ctx)
+	rows := 0
+	for i := 1; i <= n && ctx.Err() == nil; i++ {
+		if progress.Due() {
+			progress.Report(rowProgress(rows, n, capacity_max, dp[rows][capacity_max]))
+		}
+		dp[i] = make([]int, capacity_max+1)
 		for j := 0; j <= capacity_max; j++ {
-			if i == 0 || j == 0 {
-				dp[i][j] = 0
-			} else if j < objects[i-1].Weight {
-				dp[i][j] = dp[i-1][j]
-			} else {
-				dp[i][j] = int(math.Max(float64(dp[i-1][j]), float64(dp[i-1][j-objects[i-1].Weight]+objects[i-1].Value)))
+			dp[i][j] = dp[i-1][j]
+			if j >= objects[i-1].Weight {
+				if v := dp[i-1][j-objects[i-1].Weight] + objects[i-1].Value; v > dp[i][j] {
+					dp[i][j] = v
+				}
 			}
 		}
+		rows = i
 	}
 
-	// Récupérer les objets sélectionnés
-	selectedObjects := make([]common.Objects, 0)
-	i, j := n, capacity_max
-	for i > 0 && j > 0 {
+	// Récupérer les indices des objets sélectionnés
+	indices := make([]int, 0)
+	i, j := rows, capacity_max
+	for i > 0 {
 		if dp[i][j] != dp[i-1][j] {
-			selectedObjects = append(selectedObjects, objects[i-1])
+			indices = append(indices, i-1)
 			j -= objects[i-1].Weight
 		}
 		i--
 	}
 
-	return dp[n][capacity_max], selectedObjects
+	progress.Finish(rowProgress(rows, n, capacity_max, dp[rows][capacity_max]))
+	return dp[rows][capacity_max], indices, dp[:rows+1]
+}
+
+/* rowProgress décrit l'avancement de la table après rows lignes sur n */
+func rowProgress(rows, n, capacity, best int) common.Progress {
+	return common.Progress{Solver: "dp", Nodes: int64(rows+1) * int64(capacity+1), Best: best, Rows: rows, TotalRows: n}
+}
+
+/* Solver adapte la programmation dynamique à l'interface common.Solver */
+type Solver struct{}
+
+func init() {
+	common.Register(Solver{})
+}
+
+func (Solver) Name() string {
+	return "dp"
+}
+
+func (Solver) Solve(ctx context.Context, inst common.Instance) (common.Result, error) {
+	if inst.Capacity < 0 {
+		return common.Result{}, fmt.Errorf("Invalid capacity %d", inst.Capacity)
+	}
+
+	startTime := time.Now()
+	value, indices, dp := knapsackTable(ctx, inst.Objects, inst.Capacity)
+	rows := len(dp) - 1
+
+	res := common.NewResult(inst, indices, rows == len(inst.Objects))
+	if rows < len(inst.Objects) {
+		// Les objets restants ne peuvent pas apporter plus que leur relaxation linéaire
+		res.Interrupt(value + algorithme_glouton.FractionalKnapsack(inst.Objects[rows:], inst.Capacity).Bound)
+		algorithme_glouton.Improve(&res, inst)
+	}
+	if res.Optimal {
+		// La borne duale se vérifie en O(n) ; à défaut, la dernière ligne de la table sert de certificat
+		if res.Certificate = common.DualCertificate(inst, res.Value); res.Certificate == nil {
+			res.Certificate = common.TableCertificate(dp[rows])
+		}
+	}
+	res.Stats.Nodes = int64(rows+1) * int64(inst.Capacity+1)
+	res.Stats.Duration = time.Since(startTime)
+	return res, nil
 }
 
 func PrintNewBag(objects []common.Objects) {
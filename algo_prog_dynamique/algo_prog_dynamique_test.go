@@ -0,0 +1,105 @@
+package algo_prog_dynamique_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"../algo_prog_dynamique"
+	"../common"
+	"../verification"
+)
+
+/* bruteForce énumère tous les sous-ensembles d'objets */
+func bruteForce(objects []common.Objects, capacity int) int {
+	best := 0
+	for mask := 0; mask < 1<<uint(len(objects)); mask++ {
+		weight, value := 0, 0
+		for i, obj := range objects {
+			if mask&(1<<uint(i)) != 0 {
+				weight += obj.Weight
+				value += obj.Value
+			}
+		}
+		if weight <= capacity && value > best {
+			best = value
+		}
+	}
+	return best
+}
+
+func randomInstance(random *rand.Rand, n int) common.Instance {
+	inst := common.Instance{Objects: make([]common.Objects, n)}
+	total := 0
+	for i := range inst.Objects {
+		inst.Objects[i] = common.Objects{Weight: 1 + random.Intn(40), Value: 1 + random.Intn(60)}
+		total += inst.Objects[i].Weight
+	}
+	inst.Capacity = random.Intn(total + 2)
+	return inst
+}
+
+func TestAgainstBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 100; trial++ {
+		inst := randomInstance(random, random.Intn(13))
+		optimum := bruteForce(inst.Objects, inst.Capacity)
+
+		value, indices := algo_prog_dynamique.KnapsackIndices(inst.Objects, inst.Capacity)
+		if res := common.NewResult(inst, indices, true); value != optimum || res.Value != value || res.Weight > inst.Capacity {
+			t.Errorf("Trial %d: table value %d, indices give %d (weight %d), optimum %d", trial, value, res.Value, res.Weight, optimum)
+		}
+
+		value, indices, _ = algo_prog_dynamique.KnapsackLowMemory(inst.Objects, inst.Capacity)
+		if res := common.NewResult(inst, indices, true); value != optimum || res.Value != value || res.Weight > inst.Capacity {
+			t.Errorf("Trial %d: Hirschberg value %d, indices give %d (weight %d), optimum %d", trial, value, res.Value, res.Weight, optimum)
+		}
+
+		if value := algo_prog_dynamique.KnapsackValue(inst.Objects, inst.Capacity); value != optimum {
+			t.Errorf("Trial %d: KnapsackValue %d, optimum %d", trial, value, optimum)
+		}
+	}
+}
+
+func TestCertificates(t *testing.T) {
+	random := rand.New(rand.NewSource(2))
+	for trial := 0; trial < 30; trial++ {
+		inst := randomInstance(random, 1+random.Intn(20))
+		for _, solver := range []common.Solver{algo_prog_dynamique.Solver{}, algo_prog_dynamique.LowMemorySolver{}} {
+			res, err := solver.Solve(context.Background(), inst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := verification.VerifyCertificate(inst, res.Value, res.Certificate); err != nil {
+				t.Errorf("%s, trial %d: %v", solver.Name(), trial, err)
+			}
+		}
+	}
+}
+
+func TestInterrupted(t *testing.T) {
+	inst := randomInstance(rand.New(rand.NewSource(3)), 50)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	value, indices, rows := algo_prog_dynamique.KnapsackContext(ctx, inst.Objects, inst.Capacity)
+	if res := common.NewResult(inst, indices, false); rows == len(inst.Objects) || res.Value != value || res.Weight > inst.Capacity {
+		t.Errorf("Table: %d rows, value %d, indices give %d (weight %d)", rows, value, res.Value, res.Weight)
+	}
+	if _, indices, _, interrupted := algo_prog_dynamique.KnapsackLowMemoryContext(ctx, inst.Objects, inst.Capacity); !interrupted || indices != nil {
+		t.Errorf("Hirschberg: interrupted %t, indices %v", interrupted, indices)
+	}
+
+	for _, solver := range []common.Solver{algo_prog_dynamique.Solver{}, algo_prog_dynamique.LowMemorySolver{}} {
+		res, err := solver.Solve(ctx, inst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := verification.Check(inst, res); err != nil {
+			t.Errorf("%s: %v", solver.Name(), err)
+		}
+		if res.Optimal || !res.Stats.Interrupted {
+			t.Errorf("%s: expected an interrupted result, got %+v", solver.Name(), res.Stats)
+		}
+	}
+}
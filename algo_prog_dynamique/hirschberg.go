@@ -0,0 +1,154 @@
+package algo_prog_dynamique
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"../algorithme_glouton"
+	"../common"
+)
+
+// KnapsackLowMemory résout le problème du sac à dos en mémoire O(capacity) : au lieu de garder toute la
+// table pour retrouver les objets choisis, on coupe les objets en deux moitiés, on calcule la dernière ligne
+// de la table pour chacune, on cherche le partage de la capacité qui donne l'optimum, puis on recommence
+// sur chaque moitié avec sa part de capacité (reconstruction à la Hirschberg).
+// Retourne la valeur optimale, les indices des objets sélectionnés et le nombre de cases calculées.
+func KnapsackLowMemory(objects []common.Objects, capacity int) (int, []int, int64) {
+	value, indices, cells, _ := KnapsackLowMemoryContext(context.Background(), objects, capacity)
+	return value, indices, cells
+}
+
+// KnapsackLowMemoryContext est KnapsackLowMemory interruptible : si le contexte est annulé, la reconstruction
+// s'arrête, aucune solution n'est renvoyée et le dernier résultat indique l'interruption.
+func KnapsackLowMemoryContext(ctx context.Context, objects []common.Objects, capacity int) (int, []int, int64, bool) {
+	h := &hirschberg{ctx: ctx, objects: objects, indices: make([]int, 0)}
+	h.solve(usefulItems(objects, capacity), capacity)
+	if h.interrupted {
+		return 0, nil, h.cells, true
+	}
+
+	value := 0
+	for _, i := range h.indices {
+		value += objects[i].Value
+	}
+	return value, h.indices, h.cells, false
+}
+
+/* usefulItems renvoie les indices des objets qui peuvent faire partie d'une solution : ni trop lourds ni sans valeur */
+func usefulItems(objects []common.Objects, capacity int) []int {
+	items := make([]int, 0, len(objects))
+	for i, obj := range objects {
+		if obj.Weight <= capacity && obj.Value > 0 {
+			items = append(items, i)
+		}
+	}
+	return items
+}
+
+/* hirschberg accumule les objets choisis au fil de la récursion */
+type hirschberg struct {
+	ctx         context.Context // nil : pas d'interruption possible
+	objects     []common.Objects
+	indices     []int
+	cells       int64
+	interrupted bool
+}
+
+/* solve ajoute à h.indices une solution optimale du sous-problème (items, capacity) */
+func (h *hirschberg) solve(items []int, capacity int) {
+	if len(items) == 0 || h.interrupted {
+		return
+	}
+	if len(items) == 1 {
+		if h.objects[items[0]].Weight <= capacity {
+			h.indices = append(h.indices, items[0])
+		}
+		return
+	}
+
+	mid := len(items) / 2
+	split := h.bestSplit(items[:mid], items[mid:], capacity)
+
+	h.solve(items[:mid], split)
+	h.solve(items[mid:], capacity-split)
+}
+
+/* bestSplit renvoie la part de la capacité à donner aux objets left pour obtenir l'optimum du sous-problème */
+func (h *hirschberg) bestSplit(left, right []int, capacity int) int {
+	f := h.lastRow(left, capacity)
+	g := h.lastRow(right, capacity)
+
+	split := 0
+	for c := 0; c <= capacity; c++ {
+		if f[c]+g[capacity-c] > f[split]+g[capacity-split] {
+			split = c
+		}
+	}
+	return split
+}
+
+/* lastRow calcule, pour chaque capacité c, la meilleure valeur atteignable avec les objets items */
+func (h *hirschberg) lastRow(items []int, capacity int) []int {
+	row := make([]int, capacity+1)
+	for _, i := range items {
+		if h.ctx != nil && h.ctx.Err() != nil {
+			h.interrupted = true
+			return row
+		}
+		w, v := h.objects[i].Weight, h.objects[i].Value
+		for c := capacity; c >= w; c-- {
+			if row[c-w]+v > row[c] {
+				row[c] = row[c-w] + v
+			}
+		}
+		h.cells += int64(capacity + 1)
+	}
+	return row
+}
+
+/* LowMemorySolver adapte la programmation dynamique en mémoire linéaire à l'interface common.Solver */
+type LowMemorySolver struct{}
+
+func init() {
+	common.Register(LowMemorySolver{})
+}
+
+func (LowMemorySolver) Name() string {
+	return "dp_hirschberg"
+}
+
+func (LowMemorySolver) Solve(ctx context.Context, inst common.Instance) (common.Result, error) {
+	if inst.Capacity < 0 {
+		return common.Result{}, fmt.Errorf("Invalid capacity %d", inst.Capacity)
+	}
+
+	startTime := time.Now()
+	_, indices, cells, interrupted := KnapsackLowMemoryContext(ctx, inst.Objects, inst.Capacity)
+
+	res := common.NewResult(inst, indices, true)
+	if interrupted {
+		res = algorithme_glouton.Incumbent(inst)
+	}
+	if res.Optimal {
+		// Sans borne duale suffisante, la dernière ligne de la table est recalculée pour servir de certificat
+		if res.Certificate = common.DualCertificate(inst, res.Value); res.Certificate == nil {
+			h := &hirschberg{objects: inst.Objects}
+			res.Certificate = common.TableCertificate(h.lastRow(usefulItems(inst.Objects, inst.Capacity), inst.Capacity))
+			cells += h.cells
+		}
+	}
+	res.Stats.Nodes = cells
+	res.Stats.Duration = time.Since(startTime)
+	return res, nil
+}
+
+/* KnapsackValue renvoie seulement la valeur optimale, en mémoire O(capacity), sans reconstruire la solution */
+func KnapsackValue(objects []common.Objects, capacity int) int {
+	if capacity < 0 {
+		return 0
+	}
+
+	h := &hirschberg{objects: objects}
+	return h.lastRow(usefulItems(objects, capacity), capacity)[capacity]
+}
@@ -1,13 +1,19 @@
 package algo_reduc_reseau
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"math/big"
+
+	"../common"
 )
 
 type Vector []*big.Int
 type Matrix []Vector
 
+var ErrDependentVectors = errors.New("Lattice basis vectors are linearly dependent")
+
 /* Fonction pour crée un vecteur de taille n */
 func CreateVector(n int) Vector {
 	v := make(Vector, n)
@@ -103,40 +109,137 @@ func GramSchmidtOrthogonalization(B Matrix) Matrix {
 	return U
 }
 
+/* Fonction LLL historique : l'erreur de LLLContext est ignorée, la base est renvoyée telle qu'elle est à l'arrêt */
 func LLL(B Matrix, delta *big.Rat, MaxIterations int) Matrix {
-	k := 1
+	reduced, _ := LLLContext(context.Background(), B, delta, MaxIterations)
+	return reduced
+}
+
+// Fonction LLL interruptible, qui émet à chaque itération l'indice k courant et le nombre d'échanges si le
+// contexte le demande. Elle suit la variante entière de Cohen (A Course in Computational Algebraic Number
+// Theory, algorithme 2.6.7) : les coefficients de Gram-Schmidt sont gardés sous la forme des entiers
+// d_i = det(Gram(b_1..b_i)) et lambda_kj = d_j·mu_kj, ce qui évite toute arithmétique rationnelle. B est réduite
+// sur place. Les vecteurs de B doivent être linéairement indépendants : si un vecteur dépendant apparaît, la
+// réduction s'arrête et renvoie la base partiellement réduite avec ErrDependentVectors ; si le contexte est
+// annulé, elle renvoie la base courante avec l'erreur du contexte. Atteindre MaxIterations n'est pas une erreur.
+func LLLContext(ctx context.Context, B Matrix, delta *big.Rat, MaxIterations int) (Matrix, error) {
 	m := len(B)
 	iter := 0
+	swaps := 0
+	progress := common.NewReporter(ctx)
+	if m < 2 {
+		progress.Finish(common.Progress{Solver: "lll", Iteration: iter, K: 1, Swaps: swaps})
+		return B, nil
+	}
 
-	U := GramSchmidtOrthogonalization(B)
+	// Indices décalés de 1 comme chez Cohen : d[0] = 1, d[i] pour les i premiers vecteurs, lambda[k][j] pour j < k
+	d := make([]*big.Int, m+1)
+	lambda := make([][]*big.Int, m+1)
+	for i := range lambda {
+		lambda[i] = make([]*big.Int, i)
+		for j := range lambda[i] {
+			lambda[i][j] = new(big.Int)
+		}
+	}
+	d[0] = big.NewInt(1)
+	d[1] = DotProduct(B[0], B[0])
+	if d[1].Sign() == 0 {
+		progress.Finish(common.Progress{Solver: "lll", Iteration: iter, K: 1, Swaps: swaps})
+		return B, fmt.Errorf("%w: vector 0 is zero", ErrDependentVectors)
+	}
+	b := func(i int) Vector { return B[i-1] }
 
-	for k < m && iter < MaxIterations {
-		iter++
+	// reduce rend |mu_kl| <= 1/2 en retranchant de b_k le multiple entier le plus proche de b_l
+	reduce := func(k, l int) {
+		twice := new(big.Int).Lsh(lambda[k][l], 1)
+		if twice.CmpAbs(d[l]) <= 0 {
+			return
+		}
+		q := twice.Add(twice, d[l])
+		q.Div(q, new(big.Int).Lsh(d[l], 1))
+		B[k-1] = VectorSub(b(k), MulVecToScal(b(l), q))
+		lambda[k][l].Sub(lambda[k][l], new(big.Int).Mul(q, d[l]))
+		for i := 1; i < l; i++ {
+			lambda[k][i].Sub(lambda[k][i], new(big.Int).Mul(q, lambda[l][i]))
+		}
+	}
 
-		for j := k - 1; j >= 0; j-- {
-			q := new(big.Rat).SetInt(DotProduct(U[j], B[k]))
-			q.Quo(q, new(big.Rat).SetInt(DotProduct(U[j], U[j])))
-			roundQ := new(big.Int).Set(q.Num())
-			roundQ.Div(roundQ, q.Denom())
-			B[k] = VectorSub(B[k], MulVecToScal(B[j], roundQ))
-			U = GramSchmidtOrthogonalization(B)
+	// swap échange b_k et b_k-1 et met à jour d et lambda sans recalculer l'orthogonalisation
+	kmax := 1
+	swap := func(k int) {
+		B[k-1], B[k-2] = B[k-2], B[k-1]
+		for j := 1; j < k-1; j++ {
+			lambda[k][j], lambda[k-1][j] = lambda[k-1][j], lambda[k][j]
+		}
+		lam := lambda[k][k-1]
+		newD := new(big.Int).Mul(d[k-2], d[k])
+		newD.Add(newD, new(big.Int).Mul(lam, lam))
+		newD.Quo(newD, d[k-1])
+		for i := k + 1; i <= kmax; i++ {
+			t := lambda[i][k]
+			next := new(big.Int).Mul(d[k], lambda[i][k-1])
+			next.Sub(next, new(big.Int).Mul(lam, t))
+			next.Quo(next, d[k-1])
+			lambda[i][k] = next
+			prev := new(big.Int).Mul(newD, t)
+			prev.Add(prev, new(big.Int).Mul(lam, next))
+			lambda[i][k-1] = prev.Quo(prev, d[k])
 		}
+		d[k-1] = newD
+	}
 
-		d := new(big.Rat).Mul(delta, new(big.Rat).SetInt(DotProduct(U[k-1], U[k-1])))
+	p, q := delta.Num(), delta.Denom()
+	k := 2
+	for k <= m && iter < MaxIterations && ctx.Err() == nil {
+		if progress.Due() {
+			progress.Report(common.Progress{Solver: "lll", Iteration: iter, K: k - 1, Swaps: swaps})
+		}
+		iter++
 
-		v := new(big.Rat).SetInt(DotProduct(U[k], U[k]))
-		v.Add(v, new(big.Rat).SetInt(DotProduct(U[k-1], U[k])))
+		// Orthogonalisation incrémentale du nouveau vecteur b_k
+		if k > kmax {
+			kmax = k
+			for j := 1; j <= k; j++ {
+				u := DotProduct(b(k), b(j))
+				for i := 1; i < j; i++ {
+					u.Mul(u, d[i])
+					u.Sub(u, new(big.Int).Mul(lambda[k][i], lambda[j][i]))
+					u.Quo(u, d[i-1])
+				}
+				if j < k {
+					lambda[k][j] = u
+				} else {
+					d[k] = u
+				}
+			}
+			if d[k].Sign() == 0 {
+				progress.Finish(common.Progress{Solver: "lll", Iteration: iter, K: k - 1, Swaps: swaps})
+				return B, fmt.Errorf("%w: vector %d is a combination of the previous ones", ErrDependentVectors, k-1)
+			}
+		}
 
-		if d.Cmp(v) < 0 {
-			B[k], B[k-1] = B[k-1], B[k]
-			k = Max(k-1, 1)
-			U = GramSchmidtOrthogonalization(B)
+		// Condition de Lovász : d_k·d_k-2 >= delta·d_k-1² - lambda_k,k-1²
+		reduce(k, k-1)
+		lam := lambda[k][k-1]
+		left := new(big.Int).Mul(d[k], d[k-2])
+		left.Add(left, new(big.Int).Mul(lam, lam))
+		left.Mul(left, q)
+		right := new(big.Int).Mul(d[k-1], d[k-1])
+		right.Mul(right, p)
+		if left.Cmp(right) < 0 {
+			swap(k)
+			swaps++
+			k = Max(k-1, 2)
 		} else {
+			for l := k - 2; l >= 1; l-- {
+				reduce(k, l)
+			}
 			k++
 		}
 	}
 
-	return B
+	progress.Finish(common.Progress{Solver: "lll", Iteration: iter, K: k - 1, Swaps: swaps})
+	return B, ctx.Err()
 }
 
 func Max(a, b int) int {
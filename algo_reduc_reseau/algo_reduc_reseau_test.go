@@ -0,0 +1,154 @@
+package algo_reduc_reseau_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"../algo_reduc_reseau"
+)
+
+func matrix(rows [][]int64) algo_reduc_reseau.Matrix {
+	M := algo_reduc_reseau.CreateMatrix(len(rows), len(rows[0]))
+	for i, row := range rows {
+		for j, v := range row {
+			M[i][j].SetInt64(v)
+		}
+	}
+	return M
+}
+
+func clone(M algo_reduc_reseau.Matrix) algo_reduc_reseau.Matrix {
+	C := algo_reduc_reseau.CreateMatrix(len(M), len(M[0]))
+	for i := range M {
+		for j := range M[i] {
+			C[i][j].Set(M[i][j])
+		}
+	}
+	return C
+}
+
+// checkReduced vérifie avec une orthogonalisation de Gram-Schmidt rationnelle exacte que la base est réduite
+// au sens de LLL pour delta = 3/4 : |mu_ij| <= 1/2 et |b*_i|² >= (3/4 - mu_i,i-1²)·|b*_i-1|².
+func checkReduced(t *testing.T, B algo_reduc_reseau.Matrix) {
+	t.Helper()
+	m, n := len(B), len(B[0])
+	star := make([][]*big.Rat, m)
+	norms := make([]*big.Rat, m)
+	half := big.NewRat(1, 2)
+
+	for i := 0; i < m; i++ {
+		star[i] = make([]*big.Rat, n)
+		for c := range star[i] {
+			star[i][c] = new(big.Rat).SetInt(B[i][c])
+		}
+		var previous *big.Rat
+		for j := 0; j < i; j++ {
+			mu := new(big.Rat)
+			for c := 0; c < n; c++ {
+				mu.Add(mu, new(big.Rat).Mul(new(big.Rat).SetInt(B[i][c]), star[j][c]))
+			}
+			mu.Quo(mu, norms[j])
+			if new(big.Rat).Abs(mu).Cmp(half) > 0 {
+				t.Errorf("Basis is not size-reduced: mu[%d][%d] = %s", i, j, mu.RatString())
+			}
+			for c := 0; c < n; c++ {
+				star[i][c].Sub(star[i][c], new(big.Rat).Mul(mu, star[j][c]))
+			}
+			previous = mu
+		}
+
+		norms[i] = new(big.Rat)
+		for c := 0; c < n; c++ {
+			norms[i].Add(norms[i], new(big.Rat).Mul(star[i][c], star[i][c]))
+		}
+		if i > 0 {
+			bound := new(big.Rat).Sub(big.NewRat(3, 4), new(big.Rat).Mul(previous, previous))
+			if norms[i].Cmp(bound.Mul(bound, norms[i-1])) < 0 {
+				t.Errorf("Lovász condition fails at index %d", i)
+			}
+		}
+	}
+}
+
+/* determinant calcule le déterminant d'une matrice carrée par élimination de Gauss rationnelle */
+func determinant(M algo_reduc_reseau.Matrix) *big.Rat {
+	n := len(M)
+	A := make([][]*big.Rat, n)
+	for i := range A {
+		A[i] = make([]*big.Rat, n)
+		for j := range A[i] {
+			A[i][j] = new(big.Rat).SetInt(M[i][j])
+		}
+	}
+
+	det := big.NewRat(1, 1)
+	for col := 0; col < n; col++ {
+		pivot := col
+		for pivot < n && A[pivot][col].Sign() == 0 {
+			pivot++
+		}
+		if pivot == n {
+			return new(big.Rat)
+		}
+		if pivot != col {
+			A[pivot], A[col] = A[col], A[pivot]
+			det.Neg(det)
+		}
+		det.Mul(det, A[col][col])
+		for i := col + 1; i < n; i++ {
+			f := new(big.Rat).Quo(A[i][col], A[col][col])
+			for j := col; j < n; j++ {
+				A[i][j].Sub(A[i][j], new(big.Rat).Mul(f, A[col][j]))
+			}
+		}
+	}
+	return det
+}
+
+func TestLLLKnownBasis(t *testing.T) {
+	B := matrix([][]int64{{1, 1, 1}, {-1, 0, 2}, {3, 5, 6}})
+	expected := matrix([][]int64{{0, 1, 0}, {1, 0, 1}, {-1, 0, 2}})
+
+	reduced := algo_reduc_reseau.LLL(B, big.NewRat(3, 4), 1000)
+	for i := range expected {
+		for j := range expected[i] {
+			if reduced[i][j].Cmp(expected[i][j]) != 0 {
+				t.Fatalf("Unexpected reduced basis %v, expected %v", reduced, expected)
+			}
+		}
+	}
+}
+
+func TestLLLReducesRandomBases(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 100; trial++ {
+		m := 2 + random.Intn(8)
+		B := algo_reduc_reseau.CreateMatrix(m, m)
+		for i := range B {
+			for j := range B[i] {
+				B[i][j].SetInt64(random.Int63n(2001) - 1000)
+			}
+		}
+		det := determinant(B)
+		if det.Sign() == 0 {
+			continue
+		}
+
+		reduced := algo_reduc_reseau.LLL(clone(B), big.NewRat(3, 4), 1<<30)
+		checkReduced(t, reduced)
+		// Les opérations de LLL sont unimodulaires : le réseau, donc |det|, est conservé
+		if got := determinant(reduced); new(big.Rat).Abs(got).Cmp(new(big.Rat).Abs(det)) != 0 {
+			t.Errorf("Trial %d: determinant changed from %s to %s", trial, det.RatString(), got.RatString())
+		}
+	}
+}
+
+func TestLLLDependentVectors(t *testing.T) {
+	B := matrix([][]int64{{1, 2, 3}, {2, 4, 6}, {0, 1, 1}})
+	if _, err := algo_reduc_reseau.LLLContext(context.Background(), B, big.NewRat(3, 4), 1000); !errors.Is(err, algo_reduc_reseau.ErrDependentVectors) {
+		t.Errorf("Expected ErrDependentVectors, got %v", err)
+	}
+}
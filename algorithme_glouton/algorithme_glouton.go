@@ -1,6 +1,7 @@
 package algorithme_glouton
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -27,16 +28,52 @@ func LoadJSONData(filename string) ([]common.Objects, error) {
 func Knapsack(data []common.Objects, capacity int) ([]common.Objects, int, int) {
 	var weight int
 	var results []common.Objects
-	for _, obj := range data {
+	for _, i := range KnapsackIndices(data, capacity) {
+		weight += data[i].Weight
+		results = append(results, data[i])
+	}
+	return results, weight, capacity - weight
+}
+
+/* KnapsackIndices renvoie les indices des objets retenus par l'algorithme glouton, dans l'ordre des données */
+func KnapsackIndices(data []common.Objects, capacity int) []int {
+	var weight int
+	var indices []int
+	for i, obj := range data {
 		if weight+obj.Weight <= capacity {
 			weight += obj.Weight
-			results = append(results, obj)
+			indices = append(indices, i)
 		} else {
 			// Si ajouter l'objet dépasse la capacité, nous arrêtons la boucle
 			break
 		}
 	}
-	return results, weight, capacity - weight
+	return indices
+}
+
+/* Solver adapte l'algorithme glouton à l'interface common.Solver */
+type Solver struct{}
+
+func init() {
+	common.Register(Solver{})
+}
+
+func (Solver) Name() string {
+	return "greedy"
+}
+
+func (Solver) Solve(ctx context.Context, inst common.Instance) (common.Result, error) {
+	startTime := time.Now()
+	if ctx.Err() != nil {
+		res := common.NewResult(inst, nil, false)
+		res.Interrupt(0)
+		return res, nil
+	}
+	indices := KnapsackIndices(inst.Objects, inst.Capacity)
+
+	res := common.NewResult(inst, indices, false)
+	res.Stats.Duration = time.Since(startTime)
+	return res, nil
 }
 
 func PrintNewBag(data []common.Objects) {
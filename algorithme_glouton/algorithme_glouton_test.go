@@ -0,0 +1,86 @@
+package algorithme_glouton_test
+
+import (
+	"testing"
+
+	"../algo_prog_dynamique"
+	"../algorithme_glouton"
+	"../common"
+	"../create_data"
+	"../verification"
+)
+
+func instances(t *testing.T) []common.Instance {
+	t.Helper()
+	var insts []common.Instance
+	for _, family := range create_data.Families() {
+		for seed := int64(1); seed <= 3; seed++ {
+			inst, _, err := create_data.Generate(create_data.Config{Family: family, N: 30, Range: 100, Seed: seed})
+			if err != nil {
+				t.Fatal(err)
+			}
+			insts = append(insts, inst)
+		}
+	}
+	return insts
+}
+
+func TestFractionalBound(t *testing.T) {
+	for k, inst := range instances(t) {
+		optimum := algo_prog_dynamique.KnapsackValue(inst.Objects, inst.Capacity)
+		f := algorithme_glouton.FractionalKnapsack(inst.Objects, inst.Capacity)
+		if f.Bound < optimum || float64(f.Bound) > f.Value {
+			t.Errorf("Instance %d: bound %d (relaxation %g), optimum %d", k, f.Bound, f.Value, optimum)
+		}
+		weight := 0
+		for _, i := range f.Indices {
+			weight += inst.Objects[i].Weight
+		}
+		if weight > inst.Capacity || (f.Split >= 0 && weight+inst.Objects[f.Split].Weight <= inst.Capacity) {
+			t.Errorf("Instance %d: whole objects weigh %d, critical object %d, capacity %d", k, weight, f.Split, inst.Capacity)
+		}
+	}
+}
+
+func TestHeuristics(t *testing.T) {
+	heuristics := map[string]func([]common.Objects, int) []int{
+		"greedy": algorithme_glouton.KnapsackIndices,
+		"skip":   algorithme_glouton.KnapsackSkip,
+		"half":   algorithme_glouton.KnapsackHalf,
+	}
+	for k, inst := range instances(t) {
+		optimum := algo_prog_dynamique.KnapsackValue(inst.Objects, inst.Capacity)
+		for name, heuristic := range heuristics {
+			res := common.NewResult(inst, heuristic(inst.Objects, inst.Capacity), false)
+			if err := verification.Check(inst, res); err != nil {
+				t.Errorf("%s, instance %d: %v", name, k, err)
+			}
+			if res.Value > optimum {
+				t.Errorf("%s, instance %d: value %d above optimum %d", name, k, res.Value, optimum)
+			}
+			if name == "half" && 2*res.Value < optimum {
+				t.Errorf("Half, instance %d: value %d below half the optimum %d", k, res.Value, optimum)
+			}
+		}
+	}
+}
+
+func TestImprove(t *testing.T) {
+	for k, inst := range instances(t) {
+		optimum := algo_prog_dynamique.KnapsackValue(inst.Objects, inst.Capacity)
+		incumbent := algorithme_glouton.Incumbent(inst)
+		if !incumbent.Stats.Interrupted || incumbent.Bound < optimum {
+			t.Errorf("Instance %d: incumbent bound %d below optimum %d, or not interrupted", k, incumbent.Bound, optimum)
+		}
+
+		res := common.NewResult(inst, nil, false)
+		res.Interrupt(optimum)
+		algorithme_glouton.Improve(&res, inst)
+		if err := verification.Check(inst, res); err != nil {
+			t.Errorf("Instance %d: %v", k, err)
+		}
+		if res.Value < incumbent.Value || res.Bound != optimum {
+			t.Errorf("Instance %d: improved value %d below the incumbent %d, or bound changed to %d", k, res.Value, incumbent.Value, res.Bound)
+		}
+	}
+}
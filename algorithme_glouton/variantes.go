@@ -0,0 +1,142 @@
+package algorithme_glouton
+
+import (
+	"context"
+	"time"
+
+	"../common"
+)
+
+/* Fractional est la solution optimale du sac à dos fractionnaire (relaxation linéaire) */
+type Fractional struct {
+	Indices  []int   // objets pris entièrement
+	Split    int     // objet critique pris en partie, -1 s'il n'y en a pas
+	Fraction float64 // fraction de l'objet critique prise, dans [0, 1[
+	Value    float64 // valeur de la relaxation linéaire
+	Bound    int     // partie entière de Value, borne supérieure de l'optimum entier
+}
+
+// FractionalKnapsack résout le sac à dos fractionnaire : les objets sont pris par rapport valeur/poids
+// décroissant jusqu'à l'objet critique, dont on prend la fraction qui remplit exactement le sac.
+func FractionalKnapsack(data []common.Objects, capacity int) Fractional {
+	res := Fractional{Indices: make([]int, 0), Split: -1}
+	remaining := capacity
+	value := 0
+
+	for _, i := range common.RatioOrder(data) {
+		obj := data[i]
+		if obj.Value <= 0 {
+			break
+		}
+		if obj.Weight <= remaining {
+			remaining -= obj.Weight
+			value += obj.Value
+			res.Indices = append(res.Indices, i)
+			continue
+		}
+
+		res.Split = i
+		res.Fraction = float64(remaining) / float64(obj.Weight)
+		res.Value = float64(value) + res.Fraction*float64(obj.Value)
+		res.Bound = value + remaining*obj.Value/obj.Weight
+		return res
+	}
+
+	res.Value = float64(value)
+	res.Bound = value
+	return res
+}
+
+/* KnapsackSkip trie lui-même les objets par rapport valeur/poids et continue après un objet qui ne rentre pas */
+func KnapsackSkip(data []common.Objects, capacity int) []int {
+	var weight int
+	indices := make([]int, 0)
+	for _, i := range common.RatioOrder(data) {
+		obj := data[i]
+		if obj.Value > 0 && weight+obj.Weight <= capacity {
+			weight += obj.Weight
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// KnapsackHalf renvoie la meilleure des deux solutions : l'algorithme glouton KnapsackSkip ou l'objet seul
+// de plus grande valeur qui rentre dans le sac. La valeur obtenue est au moins la moitié de l'optimum.
+func KnapsackHalf(data []common.Objects, capacity int) []int {
+	indices := KnapsackSkip(data, capacity)
+	value := 0
+	for _, i := range indices {
+		value += data[i].Value
+	}
+
+	best := -1
+	for i, obj := range data {
+		if obj.Weight <= capacity && obj.Value > value && (best < 0 || obj.Value > data[best].Value) {
+			best = i
+		}
+	}
+	if best >= 0 {
+		return []int{best}
+	}
+	return indices
+}
+
+/* SkipSolver adapte KnapsackSkip à l'interface common.Solver */
+type SkipSolver struct{}
+
+/* HalfSolver adapte KnapsackHalf (1/2-approximation) à l'interface common.Solver */
+type HalfSolver struct{}
+
+func init() {
+	common.Register(SkipSolver{})
+	common.Register(HalfSolver{})
+}
+
+func (SkipSolver) Name() string {
+	return "greedy_skip"
+}
+
+func (SkipSolver) Solve(ctx context.Context, inst common.Instance) (common.Result, error) {
+	return solveWithBound(ctx, inst, KnapsackSkip), nil
+}
+
+func (HalfSolver) Name() string {
+	return "greedy_half"
+}
+
+func (HalfSolver) Solve(ctx context.Context, inst common.Instance) (common.Result, error) {
+	return solveWithBound(ctx, inst, KnapsackHalf), nil
+}
+
+/* solveWithBound exécute une variante gloutonne et la compare à la borne de la relaxation linéaire */
+func solveWithBound(ctx context.Context, inst common.Instance, knapsack func([]common.Objects, int) []int) common.Result {
+	startTime := time.Now()
+	if ctx.Err() != nil {
+		res := common.NewResult(inst, nil, false)
+		res.Interrupt(0)
+		return res
+	}
+	indices := knapsack(inst.Objects, inst.Capacity)
+
+	res := common.NewResult(inst, indices, false)
+	res.Bound = FractionalKnapsack(inst.Objects, inst.Capacity).Bound
+	res.Optimal = res.Value == res.Bound
+	res.Stats.Duration = time.Since(startTime)
+	return res
+}
+
+// Incumbent renvoie la solution de KnapsackHalf, accompagnée de la borne de la relaxation linéaire et marquée
+// comme interrompue : c'est le résultat des solveurs exacts arrêtés avant d'avoir trouvé une solution.
+func Incumbent(inst common.Instance) common.Result {
+	res := common.NewResult(inst, KnapsackHalf(inst.Objects, inst.Capacity), false)
+	res.Interrupt(FractionalKnapsack(inst.Objects, inst.Capacity).Bound)
+	return res
+}
+
+/* Improve remplace la solution d'un résultat interrompu par celle de KnapsackHalf si elle est meilleure, sans toucher à la borne */
+func Improve(res *common.Result, inst common.Instance) {
+	if greedy := common.NewResult(inst, KnapsackHalf(inst.Objects, inst.Capacity), false); greedy.Value > res.Value {
+		res.Indices, res.Value, res.Weight = greedy.Indices, greedy.Value, greedy.Weight
+	}
+}
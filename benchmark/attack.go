@@ -0,0 +1,112 @@
+package benchmark
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"math/rand"
+	"time"
+
+	"../algo_reduc_reseau"
+	"../lll_merkel_hellman"
+)
+
+/* AttackConfig décrit les expériences d'attaque par réseau ; les champs nuls prennent leur valeur par défaut */
+type AttackConfig struct {
+	Sizes     []int         `json:"sizes"`     // nombres de poids, 16 et 32 par défaut
+	Densities []float64     `json:"densities"` // densités visées n / log2(max a_i), de 0,3 à 1,5 par défaut
+	Trials    int           `json:"trials"`    // instances tirées par taille et par densité, 10 par défaut
+	Seed      int64         `json:"seed"`      // graine du générateur, 1 par défaut
+	Timeout   time.Duration `json:"timeout"`   // durée maximale d'une attaque, 10 s par défaut
+}
+
+func (cfg AttackConfig) withDefaults() AttackConfig {
+	if len(cfg.Sizes) == 0 {
+		cfg.Sizes = []int{16, 32}
+	}
+	if len(cfg.Densities) == 0 {
+		cfg.Densities = []float64{0.3, 0.5, 0.7, 0.9, 1.1, 1.3, 1.5}
+	}
+	if cfg.Trials <= 0 {
+		cfg.Trials = 10
+	}
+	if cfg.Seed == 0 {
+		cfg.Seed = 1
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return cfg
+}
+
+/* AttackRecord est le résultat d'une attaque par réseau sur une instance de somme de sous-ensemble */
+type AttackRecord struct {
+	Attack      string  `json:"attack"`
+	N           int     `json:"n"`
+	Bits        int     `json:"bits"`    // taille en bits des poids
+	Target      float64 `json:"target"`  // densité visée par la configuration
+	Density     float64 `json:"density"` // densité effective de l'instance
+	Trial       int     `json:"trial"`
+	Success     bool    `json:"success"`
+	TimedOut    bool    `json:"timed_out"` // échec par dépassement de Timeout
+	Nanoseconds int64   `json:"ns"`
+	Error       string  `json:"error,omitempty"`
+}
+
+// RunAttacks mesure le taux de succès de l'attaque de faible densité (lll_merkel_hellman.LowDensityAttack) :
+// pour chaque taille et chaque densité, Trials instances aléatoires sont tirées, avec des poids de n / densité
+// bits et une cible formée d'un sous-ensemble aléatoire. Une attaque réussit si elle trouve un sous-ensemble de
+// la bonne somme, pas forcément celui qui a été tiré ; une attaque interrompue par Timeout compte comme un échec
+// (TimedOut). L'annulation du contexte arrête les expériences et renvoie les résultats déjà obtenus.
+func RunAttacks(ctx context.Context, cfg AttackConfig, progress func(AttackRecord)) ([]AttackRecord, error) {
+	cfg = cfg.withDefaults()
+	random := rand.New(rand.NewSource(cfg.Seed))
+	records := make([]AttackRecord, 0)
+
+	for _, n := range cfg.Sizes {
+		for _, density := range cfg.Densities {
+			bits := int(math.Round(float64(n) / density))
+			for trial := 0; trial < cfg.Trials; trial++ {
+				if err := ctx.Err(); err != nil {
+					return records, err
+				}
+
+				weights := make([]*big.Int, n)
+				target := new(big.Int)
+				for i := range weights {
+					weights[i] = new(big.Int).Rand(random, new(big.Int).Lsh(big.NewInt(1), uint(bits)))
+					if random.Intn(2) == 1 {
+						target.Add(target, weights[i])
+					}
+				}
+
+				r := AttackRecord{Attack: "low_density", N: n, Bits: bits, Target: density, Density: lll_merkel_hellman.Density(weights), Trial: trial}
+				attackCtx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+				start := time.Now()
+				_, err := lll_merkel_hellman.LowDensityAttack(attackCtx, weights, target)
+				r.Nanoseconds = time.Since(start).Nanoseconds()
+				cancel()
+
+				// Une attaque qui dépasse Timeout ou ne trouve rien est un échec, pas une erreur : l'écarter des
+				// statistiques surestimerait le taux de succès aux densités élevées, où les échecs sont lents
+				switch {
+				case err == nil:
+					r.Success = true
+				case ctx.Err() != nil:
+					return records, ctx.Err()
+				case errors.Is(err, context.DeadlineExceeded):
+					r.TimedOut = true
+				case errors.Is(err, lll_merkel_hellman.ErrAttackFailed), errors.Is(err, algo_reduc_reseau.ErrDependentVectors):
+				default:
+					r.Error = err.Error()
+				}
+				records = append(records, r)
+				if progress != nil {
+					progress(r)
+				}
+			}
+		}
+	}
+	return records, nil
+}
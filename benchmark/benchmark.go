@@ -1,35 +1,260 @@
-package main
+package benchmark
 
 import (
-	"testing"
+	"context"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
 
-	"../tools"
+	"../common"
+	"../create_data"
+	"../fptas"
+	"../reduction"
+	_ "../tools"
 )
 
-func BenchmarkGreedyAlgorithm(b *testing.B) {
-	data, err := tools.LoadDataFromFile("data.json")
+/* DefaultTimeout est la durée maximale par défaut d'une exécution */
+const DefaultTimeout = 2 * time.Second
+
+/* Config décrit la matrice d'expériences (solveur × famille × taille × graine) ; les champs nuls prennent leur valeur par défaut */
+type Config struct {
+	Solvers     []string             `json:"solvers"`     // noms des solveurs, tous les solveurs enregistrés par défaut
+	Families    []create_data.Family `json:"families"`    // familles d'instances, toutes par défaut
+	Sizes       []int                `json:"sizes"`       // nombres d'objets, 10, 20, 50 et 100 par défaut
+	Seeds       []int64              `json:"seeds"`       // graines du générateur, 1, 2 et 3 par défaut
+	Range       int                  `json:"range"`       // les poids sont tirés dans [1, Range], 1000 par défaut
+	Repetitions int                  `json:"repetitions"` // exécutions de chaque solveur sur chaque instance, 5 par défaut
+	Timeout     time.Duration        `json:"timeout"`     // durée maximale d'une exécution, 2 s par défaut
+	Reference   string               `json:"reference"`   // solveur exact qui fournit l'optimum de chaque instance, minknap par défaut
+	Reduce      bool                 `json:"reduce"`      // mesurer aussi chaque solveur 0/1 précédé de la réduction (nom suffixé par _reduced)
+	Epsilons    []float64            `json:"epsilons"`    // précisions du FPTAS mesurées en plus de Solvers (solveurs fptas_<ε>), aucune par défaut
+}
+
+func (cfg Config) withDefaults() Config {
+	if len(cfg.Solvers) == 0 {
+		cfg.Solvers = common.SolverNames()
+	}
+	if len(cfg.Families) == 0 {
+		cfg.Families = create_data.Families()
+	}
+	if len(cfg.Sizes) == 0 {
+		cfg.Sizes = []int{10, 20, 50, 100}
+	}
+	if len(cfg.Seeds) == 0 {
+		cfg.Seeds = []int64{1, 2, 3}
+	}
+	if cfg.Range <= 0 {
+		cfg.Range = 1000
+	}
+	if cfg.Repetitions <= 0 {
+		cfg.Repetitions = 5
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.Reference == "" {
+		cfg.Reference = "minknap"
+	}
+	return cfg
+}
+
+/* Record est la mesure d'une exécution d'un solveur sur une instance */
+type Record struct {
+	Solver      string             `json:"solver"`
+	Family      create_data.Family `json:"family"`
+	N           int                `json:"n"`
+	Seed        int64              `json:"seed"`
+	Repetition  int                `json:"repetition"`
+	Capacity    int                `json:"capacity"`
+	Value       int                `json:"value"`
+	Bound       int                `json:"bound"`   // borne supérieure annoncée par le solveur, 0 si inconnue
+	Optimum     int                `json:"optimum"` // valeur optimale donnée par le solveur de référence, 0 si inconnue
+	Optimal     bool               `json:"optimal"`
+	Interrupted bool               `json:"interrupted"`
+	Nodes       int64              `json:"nodes"`
+	Fixed       int                `json:"fixed"`   // objets fixés par la réduction préalable, 0 sans réduction
+	Epsilon     float64            `json:"epsilon"` // précision du FPTAS, 0 pour les autres solveurs
+	Nanoseconds int64              `json:"ns"`      // temps d'exécution mesuré autour de Solve
+	Allocs      uint64             `json:"allocs"`  // nombre d'allocations pendant l'exécution
+	Bytes       uint64             `json:"bytes"`   // octets alloués pendant l'exécution
+	Error       string             `json:"error,omitempty"`
+}
+
+/* Duration renvoie le temps d'exécution de la mesure */
+func (r Record) Duration() time.Duration {
+	return time.Duration(r.Nanoseconds)
+}
+
+/* Environment décrit la machine sur laquelle les mesures ont été faites */
+type Environment struct {
+	GoVersion string    `json:"go_version"`
+	OS        string    `json:"os"`
+	Arch      string    `json:"arch"`
+	CPUs      int       `json:"cpus"`
+	Started   time.Time `json:"started"`
+}
+
+/* Results regroupe la configuration d'une campagne de mesures, son environnement, toutes les mesures et les éventuelles attaques */
+type Results struct {
+	Config      Config         `json:"config"`
+	Environment Environment    `json:"environment"`
+	Records     []Record       `json:"records"`
+	Attacks     []AttackRecord `json:"attacks,omitempty"` // écrites seulement en JSON
+}
+
+// Run exécute toute la matrice d'expériences : chaque instance est générée par create_data, son optimum calculé
+// une fois par le solveur de référence, puis chaque solveur est exécuté Repetitions fois, dans la limite de
+// Timeout par exécution ; chaque précision de Epsilons ajoute un FPTAS, et avec Reduce, chaque solveur du sac 0/1
+// est aussi mesuré derrière reduction.Wrap, les solveurs de variantes n'ayant pas de réduction. Une erreur d'un solveur est enregistrée dans sa mesure sans arrêter la campagne ;
+// l'annulation du contexte l'arrête et renvoie les mesures déjà faites. progress, s'il n'est pas nil, reçoit
+// chaque mesure dès qu'elle est faite.
+func Run(ctx context.Context, cfg Config, progress func(Record)) (Results, error) {
+	cfg = cfg.withDefaults()
+	results := Results{
+		Config: cfg,
+		Environment: Environment{
+			GoVersion: runtime.Version(),
+			OS:        runtime.GOOS,
+			Arch:      runtime.GOARCH,
+			CPUs:      runtime.NumCPU(),
+			Started:   time.Now(),
+		},
+		Records: make([]Record, 0),
+	}
+
+	solvers := make([]common.Solver, 0, len(cfg.Solvers)+len(cfg.Epsilons))
+	names := make(map[string]bool)
+	for _, name := range cfg.Solvers {
+		s, err := Lookup(name)
+		if err != nil {
+			return results, err
+		}
+		solvers = append(solvers, s)
+		names[s.Name()] = true
+	}
+	for _, eps := range cfg.Epsilons {
+		if eps <= 0 || eps >= 1 {
+			return results, fmt.Errorf("Epsilon must be in ]0, 1[, got %g", eps)
+		}
+		if s := (fptas.Solver{Epsilon: eps}); !names[s.Name()] {
+			solvers = append(solvers, s)
+			names[s.Name()] = true
+		}
+	}
+	if cfg.Reduce {
+		solvers = append(solvers, reduced(solvers)...)
+	}
+	reference, err := Lookup(cfg.Reference)
 	if err != nil {
-		b.Fatal(err)
+		return results, err
 	}
 
-	capacity := 80
-	for n := 0; n < b.N; n++ {
-		tools.SolveKnapsackWithGreedyAlgorithm(data, capacity)
+	for _, family := range cfg.Families {
+		for _, n := range cfg.Sizes {
+			for _, seed := range cfg.Seeds {
+				inst, _, err := create_data.Generate(create_data.Config{Family: family, N: n, Range: cfg.Range, Seed: seed})
+				if err != nil {
+					return results, fmt.Errorf("Failed to generate %s instance of size %d: %v", family, n, err)
+				}
+
+				optimum := 0
+				if ref := Measure(ctx, reference, inst, cfg.Timeout); ref.Optimal {
+					optimum = ref.Value
+				}
+
+				for _, s := range solvers {
+					for rep := 0; rep < cfg.Repetitions; rep++ {
+						if err := ctx.Err(); err != nil {
+							return results, err
+						}
+
+						r := Measure(ctx, s, inst, cfg.Timeout)
+						r.Family, r.N, r.Seed, r.Repetition, r.Optimum = family, n, seed, rep, optimum
+						results.Records = append(results.Records, r)
+						if progress != nil {
+							progress(r)
+						}
+					}
+				}
+			}
+		}
 	}
+	return results, nil
 }
 
-func BenchmarkDynamicProgramming(b *testing.B) {
-	filename := "data.json"
-	capacity := 80
-	for n := 0; n < b.N; n++ {
-		tools.SolveKnapsackWithDynamicProgramming(filename, capacity)
+// Lookup renvoie le solveur enregistré sous le nom donné, ou le FPTAS de précision ε pour un nom fptas_<ε> : les
+// noms des mesures faites avec Epsilons peuvent ainsi être repassés dans Solvers.
+func Lookup(name string) (common.Solver, error) {
+	s, err := common.Lookup(name)
+	if err == nil || !strings.HasPrefix(name, "fptas_") {
+		return s, err
+	}
+	eps, parseErr := strconv.ParseFloat(strings.TrimPrefix(name, "fptas_"), 64)
+	if parseErr != nil || eps <= 0 || eps >= 1 {
+		return nil, err
 	}
+	return fptas.Solver{Epsilon: eps}, nil
 }
 
-func BenchmarkExhaustiveSearch(b *testing.B) {
-	filename := "data.json"
-	capacity := 80
-	for n := 0; n < b.N; n++ {
-		tools.SolveKnapsackWithExhaustiveSearch(filename, capacity)
+/* epsilon renvoie la précision du solveur s'il s'agit d'un FPTAS, éventuellement précédé de la réduction, 0 sinon */
+func epsilon(s common.Solver) float64 {
+	switch s := s.(type) {
+	case fptas.Solver:
+		return s.Epsilon
+	case reduction.Solver:
+		return epsilon(s.Inner)
+	}
+	return 0
+}
+
+/* reduced renvoie les solveurs du sac 0/1 parmi solvers, précédés de la réduction */
+func reduced(solvers []common.Solver) []common.Solver {
+	zeroOne := make(map[string]bool)
+	for _, name := range common.SolverNames() {
+		zeroOne[name] = true
+	}
+
+	wrapped := make([]common.Solver, 0, len(solvers))
+	for _, s := range solvers {
+		if zeroOne[s.Name()] || epsilon(s) > 0 {
+			wrapped = append(wrapped, reduction.Wrap(s))
+		}
+	}
+	return wrapped
+}
+
+// Measure exécute une fois le solveur sur l'instance et mesure le temps écoulé ainsi que les allocations faites
+// pendant l'exécution (différence des compteurs de runtime.MemStats, après un ramasse-miettes préalable).
+func Measure(ctx context.Context, s common.Solver, inst common.Instance, timeout time.Duration) Record {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	res, err := s.Solve(ctx, inst)
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	r := Record{
+		Solver:      s.Name(),
+		Capacity:    inst.Capacity,
+		Value:       res.Value,
+		Bound:       res.Bound,
+		Optimal:     res.Optimal,
+		Interrupted: res.Stats.Interrupted,
+		Nodes:       res.Stats.Nodes,
+		Fixed:       res.Stats.Fixed,
+		Epsilon:     epsilon(s),
+		Nanoseconds: elapsed.Nanoseconds(),
+		Allocs:      after.Mallocs - before.Mallocs,
+		Bytes:       after.TotalAlloc - before.TotalAlloc,
+	}
+	if err != nil {
+		r.Error = err.Error()
 	}
+	return r
 }
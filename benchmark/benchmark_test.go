@@ -0,0 +1,346 @@
+package benchmark_test
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"../benchmark"
+	"../create_data"
+	"../tools"
+)
+
+func BenchmarkGreedyAlgorithm(b *testing.B) {
+	data, err := tools.LoadDataFromFile("../data.json")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	capacity := 80
+	for n := 0; n < b.N; n++ {
+		tools.SolveKnapsackWithGreedyAlgorithm(data, capacity)
+	}
+}
+
+func BenchmarkDynamicProgramming(b *testing.B) {
+	filename := "../data.json"
+	capacity := 80
+	for n := 0; n < b.N; n++ {
+		tools.SolveKnapsackWithDynamicProgramming(filename, capacity)
+	}
+}
+
+func BenchmarkExhaustiveSearch(b *testing.B) {
+	filename := "../data.json"
+	capacity := 80
+	for n := 0; n < b.N; n++ {
+		tools.SolveKnapsackWithExhaustiveSearch(filename, capacity)
+	}
+}
+
+func TestRunRoundTrip(t *testing.T) {
+	cfg := benchmark.Config{
+		Solvers:     []string{"dp", "greedy"},
+		Families:    []create_data.Family{create_data.Uncorrelated},
+		Sizes:       []int{5, 10},
+		Seeds:       []int64{1},
+		Repetitions: 2,
+		Reduce:      true,
+		Epsilons:    []float64{0.05},
+	}
+	results, err := benchmark.Run(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results.Records) != 6*2*2 {
+		t.Fatalf("Expected 24 records, got %d", len(results.Records))
+	}
+	for _, r := range results.Records {
+		if r.Nanoseconds <= 0 || r.Optimum == 0 || r.Value > r.Optimum || (r.Solver == "dp" && r.Value != r.Optimum) {
+			t.Errorf("Unexpected record %+v", r)
+		}
+	}
+
+	for _, name := range []string{"results.json", "results.csv"} {
+		filename := filepath.Join(t.TempDir(), name)
+		if err := benchmark.WriteFile(filename, results); err != nil {
+			t.Fatal(err)
+		}
+		read, err := benchmark.ReadFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(read.Records, results.Records) {
+			t.Errorf("%s: records changed after a round trip", name)
+		}
+	}
+}
+
+func TestRunWithReduction(t *testing.T) {
+	cfg := benchmark.Config{
+		Solvers:     []string{"dp", "multiple_choice"},
+		Families:    []create_data.Family{create_data.Uncorrelated},
+		Sizes:       []int{30},
+		Seeds:       []int64{1, 2},
+		Repetitions: 1,
+		Reduce:      true,
+	}
+	results, err := benchmark.Run(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Le solveur de variante n'a pas de version réduite
+	fixed := make(map[string]int)
+	for _, r := range results.Records {
+		fixed[r.Solver] += r.Fixed
+		if r.Solver == "dp_reduced" && r.Value != r.Optimum {
+			t.Errorf("Reduced solver missed the optimum: %+v", r)
+		}
+	}
+	if len(fixed) != 3 || fixed["dp"] != 0 || fixed["dp_reduced"] == 0 {
+		t.Errorf("Expected dp, dp_reduced and multiple_choice, with objects fixed by dp_reduced only: %v", fixed)
+	}
+}
+
+func TestRunWithEpsilons(t *testing.T) {
+	cfg := benchmark.Config{
+		Solvers:     []string{"fptas", "fptas_0.3"},
+		Families:    []create_data.Family{create_data.StronglyCorrelated},
+		Sizes:       []int{20},
+		Seeds:       []int64{1, 2},
+		Repetitions: 1,
+		Epsilons:    []float64{0.3, 0.01, 0.1},
+	}
+	results, err := benchmark.Run(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Une précision déjà demandée par son nom n'est mesurée qu'une fois
+	epsilons := make(map[string]float64)
+	for _, r := range results.Records {
+		epsilons[r.Solver] = r.Epsilon
+		if r.Error != "" || float64(r.Value) < (1-r.Epsilon)*float64(r.Optimum) {
+			t.Errorf("Record outside the (1 - ε) guarantee: %+v", r)
+		}
+	}
+	expected := map[string]float64{"fptas": 0.1, "fptas_0.3": 0.3, "fptas_0.01": 0.01}
+	if !reflect.DeepEqual(epsilons, expected) || len(results.Records) != 3*2 {
+		t.Errorf("Expected solvers %v with one record per seed, got %v in %d records", expected, epsilons, len(results.Records))
+	}
+
+	for _, eps := range []float64{0, 1} {
+		cfg.Epsilons = []float64{eps}
+		if _, err := benchmark.Run(context.Background(), cfg, nil); err == nil {
+			t.Errorf("Expected an error for epsilon %g", eps)
+		}
+	}
+	if _, err := benchmark.Lookup("fptas_2"); err == nil {
+		t.Error("Expected an error for fptas_2")
+	}
+}
+
+func TestMannWhitney(t *testing.T) {
+	a := []float64{1, 2, 3, 4, 5, 6, 7, 8}
+	b := []float64{11, 12, 13, 14, 15, 16, 17, 18}
+	if u, p := benchmark.MannWhitney(a, b); u != 0 || p > 0.01 {
+		t.Errorf("Disjoint samples: expected U=0 and p<0.01, got U=%g p=%g", u, p)
+	}
+	if _, p := benchmark.MannWhitney(a, a); p < 0.9 {
+		t.Errorf("Identical samples: expected p close to 1, got %g", p)
+	}
+
+	// Loi exacte sans égalité : 2 classements sur C(10, 5) = 252 sont aussi extrêmes que deux échantillons disjoints
+	if _, p := benchmark.MannWhitney(a[:5], b[:5]); math.Abs(p-2.0/252) > 1e-12 {
+		t.Errorf("Disjoint samples of 5: expected exact p=2/252, got %g", p)
+	}
+	if _, p := benchmark.MannWhitney(a[:3], b[:3]); math.Abs(p-0.1) > 1e-12 {
+		t.Errorf("Disjoint samples of 3: expected exact p=0.1, got %g", p)
+	}
+	if u, p := benchmark.MannWhitney([]float64{1, 4, 5}, []float64{2, 3, 6}); u != 4 || p != 1 {
+		t.Errorf("Interleaved samples: expected U=4 and p=1, got U=%g p=%g", u, p)
+	}
+}
+
+func TestCompareFlagsRegression(t *testing.T) {
+	results := func(solver string, scale int64) benchmark.Results {
+		var r benchmark.Results
+		for k := int64(0); k < 10; k++ {
+			r.Records = append(r.Records, benchmark.Record{Solver: solver, N: 10, Nanoseconds: scale * (100 + k)})
+		}
+		return r
+	}
+	before, after := results("dp", 1), results("dp", 2)
+	after.Records = append(after.Records, results("greedy", 1).Records...)
+
+	comparisons := benchmark.Compare(before, after, benchmark.CompareOptions{})
+	if len(comparisons) != 1 || !comparisons[0].Regression {
+		t.Fatalf("Expected one regression, got %+v", comparisons)
+	}
+	if comparisons = benchmark.Compare(before, before, benchmark.CompareOptions{}); comparisons[0].Regression {
+		t.Errorf("Identical runs flagged as a regression: %+v", comparisons[0])
+	}
+}
+
+func TestCompareGroupsBySeed(t *testing.T) {
+	// Deux instances très différentes : mélangées, l'écart entre graines noierait le ralentissement de 20 %
+	results := func(scale float64) benchmark.Results {
+		var r benchmark.Results
+		for seed := int64(1); seed <= 2; seed++ {
+			for k := int64(0); k < 5; k++ {
+				ns := float64(seed*seed*seed*1000 + k)
+				r.Records = append(r.Records, benchmark.Record{Solver: "dp", N: 10, Seed: seed, Nanoseconds: int64(scale * ns)})
+			}
+		}
+		return r
+	}
+
+	comparisons := benchmark.Compare(results(1), results(1.2), benchmark.CompareOptions{})
+	if len(comparisons) != 2 {
+		t.Fatalf("Expected one comparison per seed, got %+v", comparisons)
+	}
+	for k, c := range comparisons {
+		if c.Seed != int64(k+1) || c.Old.Count != 5 || !c.Regression {
+			t.Errorf("Seed %d: expected a regression over 5 runs, got %+v", k+1, c)
+		}
+	}
+}
+
+func TestCompareSizes(t *testing.T) {
+	// dp est 20 % plus lent sur les deux graines de n = 10, inchangé pour n = 20
+	results := func(scale float64) benchmark.Results {
+		var r benchmark.Results
+		for _, n := range []int{10, 20} {
+			for seed := int64(1); seed <= 2; seed++ {
+				for k := int64(0); k < 5; k++ {
+					ns := float64(seed*1000 + k)
+					if n == 10 {
+						ns *= scale
+					}
+					r.Records = append(r.Records, benchmark.Record{Solver: "dp", N: n, Seed: seed, Nanoseconds: int64(ns)})
+				}
+			}
+		}
+		return r
+	}
+
+	sizes := benchmark.CompareSizes(benchmark.Compare(results(1), results(1.2), benchmark.CompareOptions{}))
+	if len(sizes) != 2 || sizes[0].N != 10 || sizes[1].N != 20 {
+		t.Fatalf("Expected one summary per size, got %+v", sizes)
+	}
+	if s := sizes[0]; s.Groups != 2 || s.Regressions != 2 || math.Abs(s.Delta-0.2) > 0.01 {
+		t.Errorf("n = 10: expected two regressions and a 20 %% delta, got %+v", s)
+	}
+	if s := sizes[1]; s.Groups != 2 || s.Regressions != 0 || s.Delta != 0 {
+		t.Errorf("n = 20: expected no change, got %+v", s)
+	}
+
+	var buf bytes.Buffer
+	if err := benchmark.WriteComparisons(&buf, benchmark.Compare(results(1), results(1.2), benchmark.CompareOptions{})); err == nil {
+		t.Error("Expected ErrRegression")
+	}
+	if !strings.Contains(buf.String(), "Par solveur et par taille") {
+		t.Errorf("Missing the per-size summary:\n%s", buf.String())
+	}
+}
+
+func TestCompareExcludesInterrupted(t *testing.T) {
+	results := func(interrupted int) benchmark.Results {
+		var r benchmark.Results
+		for k := int64(0); k < 10; k++ {
+			r.Records = append(r.Records, benchmark.Record{Solver: "dp", N: 10, Nanoseconds: 100 + k})
+		}
+		// Une exécution interrompue s'arrête à la limite de temps : son temps ne dit rien du solveur
+		for k := 0; k < interrupted; k++ {
+			r.Records = append(r.Records, benchmark.Record{Solver: "dp", N: 10, Nanoseconds: 1, Interrupted: true})
+		}
+		return r
+	}
+
+	c := benchmark.Compare(results(3), results(3), benchmark.CompareOptions{})[0]
+	if c.Old.Count != 10 || c.Old.Interrupted != 3 || c.Old.Median != 104 || c.Regression || c.Improvement {
+		t.Errorf("Interrupted runs must be left out of the timings: %+v", c)
+	}
+	// Une interruption de plus n'est pas significative, dix sur vingt exécutions le sont
+	if c = benchmark.Compare(results(0), results(1), benchmark.CompareOptions{})[0]; c.Regression || c.PInterrupts < 0.05 {
+		t.Errorf("One more interruption must not be flagged as a regression: %+v", c)
+	}
+	if c = benchmark.Compare(results(0), results(10), benchmark.CompareOptions{})[0]; !c.Regression || c.PInterrupts >= 0.05 {
+		t.Errorf("Significantly more interruptions must be flagged as a regression: %+v", c)
+	}
+	if c = benchmark.Compare(results(10), results(0), benchmark.CompareOptions{})[0]; !c.Improvement {
+		t.Errorf("Significantly fewer interruptions must be flagged as an improvement: %+v", c)
+	}
+	// Au-dessous du seuil, même une hausse significative de la proportion n'est pas une régression
+	if c = benchmark.Compare(results(0), results(10), benchmark.CompareOptions{Threshold: 0.6})[0]; c.Regression {
+		t.Errorf("An interruption rate increase below the threshold must not be flagged: %+v", c)
+	}
+
+	var buf bytes.Buffer
+	if err := benchmark.WriteReport(&buf, results(3)); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "exclues des médianes : dp : 3.") {
+		t.Errorf("Report does not count the interrupted runs")
+	}
+}
+
+func TestReport(t *testing.T) {
+	cfg := benchmark.Config{
+		Solvers:     []string{"greedy", "fptas"},
+		Families:    []create_data.Family{create_data.Uncorrelated},
+		Sizes:       []int{5, 10},
+		Seeds:       []int64{1},
+		Repetitions: 1,
+		Epsilons:    []float64{0.5},
+	}
+	results, err := benchmark.Run(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attacks := benchmark.AttackConfig{Sizes: []int{8}, Densities: []float64{0.4}, Trials: 3}
+	if results.Attacks, err = benchmark.RunAttacks(context.Background(), attacks, nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range results.Attacks {
+		if !a.Success {
+			t.Errorf("Low-density attack failed at density %g: %+v", a.Density, a)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := benchmark.WriteReport(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+	report := buf.String()
+	if n := strings.Count(report, "<svg"); n != 5 {
+		t.Errorf("Expected 5 charts, got %d", n)
+	}
+	if !strings.Contains(report, "Qualité du FPTAS en fonction de ε") {
+		t.Error("Report has no chart against ε")
+	}
+	for _, external := range []string{"src=", "href=", "<script", "@import"} {
+		if strings.Contains(report, external) {
+			t.Errorf("Report is not self-contained: found %q", external)
+		}
+	}
+}
+
+func TestAttackTimeoutIsFailure(t *testing.T) {
+	cfg := benchmark.AttackConfig{Sizes: []int{24}, Densities: []float64{0.5}, Trials: 2, Timeout: time.Nanosecond}
+	attacks, err := benchmark.RunAttacks(context.Background(), cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range attacks {
+		if a.Success || !a.TimedOut || a.Error != "" {
+			t.Errorf("Expected a timed-out failure, got %+v", a)
+		}
+	}
+}
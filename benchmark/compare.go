@@ -0,0 +1,426 @@
+package benchmark
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	"../create_data"
+)
+
+var ErrRegression = errors.New("Performance regression detected")
+
+/* CompareOptions paramètre la comparaison de deux campagnes ; les champs nuls prennent leur valeur par défaut */
+type CompareOptions struct {
+	Threshold float64 // hausse relative de la médiane à partir de laquelle une différence compte, 0.1 par défaut
+	Alpha     float64 // seuil de significativité du test de Mann-Whitney, 0.05 par défaut
+}
+
+// Summary résume les temps d'un groupe de mesures : médiane et intervalle de confiance à 95 % de la médiane.
+// Les mesures interrompues par la limite de temps n'ont pas de temps significatif : elles sont exclues de Count
+// et des statistiques, et seulement comptées dans Interrupted.
+type Summary struct {
+	Count       int
+	Interrupted int
+	Median      time.Duration
+	Low         time.Duration
+	High        time.Duration
+}
+
+// Comparison compare les temps d'un solveur sur une même instance (famille, taille et graine) entre deux
+// campagnes : seules les répétitions varient, pas l'instance.
+type Comparison struct {
+	Solver      string
+	Family      create_data.Family
+	N           int
+	Seed        int64
+	Old, New    Summary
+	Delta       float64 // variation relative de la médiane, positive si la nouvelle campagne est plus lente
+	P           float64 // p-valeur bilatérale du test de Mann-Whitney
+	PInterrupts float64 // p-valeur bilatérale du test exact de Fisher sur la proportion d'exécutions interrompues
+	Regression  bool    // significativement plus d'interruptions, ou plus lent, d'au moins Threshold
+	Improvement bool    // significativement moins d'interruptions, ou plus rapide, d'au moins Threshold
+}
+
+// SizeComparison résume les comparaisons d'un solveur pour une taille d'instance, toutes familles et graines
+// confondues. Les temps d'instances différentes ne sont pas mélangés : ce sont les variations mesurées
+// instance par instance qui sont agrégées.
+type SizeComparison struct {
+	Solver         string
+	N              int
+	Groups         int     // nombre d'instances comparées
+	Delta          float64 // moyenne géométrique des rapports des médianes moins 1, sur les instances qui ont des temps des deux côtés
+	Regressions    int     // instances dont la comparaison est une régression
+	Improvements   int     // instances dont la comparaison est une amélioration
+	OldInterrupted int     // exécutions interrompues, toutes instances confondues
+	NewInterrupted int
+}
+
+type groupKey struct {
+	solver string
+	family create_data.Family
+	n      int
+	seed   int64
+}
+
+/* group contient les temps des mesures terminées d'un groupe et le nombre de mesures interrompues */
+type group struct {
+	times       []float64
+	interrupted int
+}
+
+/* durations regroupe les mesures sans erreur par solveur, famille, taille et graine, en mettant à part les interrompues */
+func durations(results Results) map[groupKey]*group {
+	groups := make(map[groupKey]*group)
+	for _, r := range results.Records {
+		if r.Error != "" {
+			continue
+		}
+		key := groupKey{r.Solver, r.Family, r.N, r.Seed}
+		if groups[key] == nil {
+			groups[key] = &group{}
+		}
+		if r.Interrupted {
+			groups[key].interrupted++
+		} else {
+			groups[key].times = append(groups[key].times, float64(r.Nanoseconds))
+		}
+	}
+	return groups
+}
+
+// Compare apparie les groupes (solveur, famille, taille, graine) présents dans les deux campagnes et compare
+// leurs temps : variation de la médiane, intervalles de confiance et test de Mann-Whitney, qui ne suppose rien
+// sur la distribution des temps. Chaque groupe est une seule instance mesurée Repetitions fois, pour que l'écart
+// entre instances de graines différentes ne masque pas celui entre les campagnes ; il faut au moins 4 mesures
+// de chaque côté pour qu'un écart soit significatif au seuil 0,05. Les mesures interrompues sont exclues des
+// temps et leurs proportions comparées par le test exact de Fisher, avec les mêmes règles : une hausse de la
+// proportion d'au moins Threshold et significative au seuil Alpha est une régression, une baisse une
+// amélioration ; sinon, les temps des mesures terminées décident. Une interruption de plus dans une campagne
+// bruitée n'est donc pas une régression. Les comparaisons sont triées par solveur, famille, taille puis graine.
+func Compare(old, new Results, opts CompareOptions) []Comparison {
+	if opts.Threshold <= 0 {
+		opts.Threshold = 0.1
+	}
+	if opts.Alpha <= 0 {
+		opts.Alpha = 0.05
+	}
+
+	oldGroups, newGroups := durations(old), durations(new)
+	comparisons := make([]Comparison, 0)
+	for key, before := range oldGroups {
+		after, ok := newGroups[key]
+		if !ok {
+			continue
+		}
+
+		c := Comparison{Solver: key.solver, Family: key.family, N: key.n, Seed: key.seed, Old: summarize(before), New: summarize(after)}
+		if c.Old.Median > 0 && c.New.Count > 0 {
+			c.Delta = float64(c.New.Median)/float64(c.Old.Median) - 1
+		}
+		_, c.P = MannWhitney(before.times, after.times)
+		c.PInterrupts = fisherExact(c.Old.Interrupted, c.Old.Count+c.Old.Interrupted, c.New.Interrupted, c.New.Count+c.New.Interrupted)
+		rateDelta := interruptRate(c.New) - interruptRate(c.Old)
+		switch {
+		case c.PInterrupts < opts.Alpha && math.Abs(rateDelta) >= opts.Threshold:
+			c.Regression = rateDelta > 0
+			c.Improvement = rateDelta < 0
+		default:
+			significant := c.P < opts.Alpha
+			c.Regression = significant && c.Delta >= opts.Threshold
+			c.Improvement = significant && c.Delta <= -opts.Threshold
+		}
+		comparisons = append(comparisons, c)
+	}
+
+	sort.Slice(comparisons, func(a, b int) bool {
+		ca, cb := comparisons[a], comparisons[b]
+		if ca.Solver != cb.Solver {
+			return ca.Solver < cb.Solver
+		}
+		if ca.Family != cb.Family {
+			return ca.Family < cb.Family
+		}
+		if ca.N != cb.N {
+			return ca.N < cb.N
+		}
+		return ca.Seed < cb.Seed
+	})
+	return comparisons
+}
+
+/* interruptRate renvoie la proportion d'exécutions interrompues d'un groupe, 0 s'il est vide */
+func interruptRate(s Summary) float64 {
+	if s.Count+s.Interrupted == 0 {
+		return 0
+	}
+	return float64(s.Interrupted) / float64(s.Count+s.Interrupted)
+}
+
+// fisherExact renvoie la p-valeur bilatérale du test exact de Fisher pour k1 succès sur n1 contre k2 sur n2 :
+// à marges fixées, le nombre de succès du premier groupe suit une loi hypergéométrique, et la p-valeur est la
+// probabilité des tableaux au plus aussi probables que celui observé. Elle vaut 1 si k1/n1 = k2/n2.
+func fisherExact(k1, n1, k2, n2 int) float64 {
+	k, n := k1+k2, n1+n2
+	if n1 == 0 || n2 == 0 || k1*n2 == k2*n1 {
+		return 1
+	}
+	logChoose := func(n, k int) float64 {
+		a, _ := math.Lgamma(float64(n + 1))
+		b, _ := math.Lgamma(float64(k + 1))
+		c, _ := math.Lgamma(float64(n - k + 1))
+		return a - b - c
+	}
+	prob := func(x int) float64 {
+		return math.Exp(logChoose(n1, x) + logChoose(n2, k-x) - logChoose(n, k))
+	}
+
+	// x succès dans le premier groupe, entre k-n2 et n1 sans dépasser k
+	low, high := k-n2, k
+	if low < 0 {
+		low = 0
+	}
+	if high > n1 {
+		high = n1
+	}
+	observed := prob(k1)
+	p := 0.0
+	for x := low; x <= high; x++ {
+		if q := prob(x); q <= observed*(1+1e-7) {
+			p += q
+		}
+	}
+	return math.Min(1, p)
+}
+
+/* CompareSizes agrège les comparaisons par solveur et par taille, triées par solveur puis taille */
+func CompareSizes(comparisons []Comparison) []SizeComparison {
+	type sizeKey struct {
+		solver string
+		n      int
+	}
+	sizes := make(map[sizeKey]*SizeComparison)
+	logRatios := make(map[sizeKey][]float64)
+	for _, c := range comparisons {
+		key := sizeKey{c.Solver, c.N}
+		sc := sizes[key]
+		if sc == nil {
+			sc = &SizeComparison{Solver: c.Solver, N: c.N}
+			sizes[key] = sc
+		}
+		sc.Groups++
+		sc.OldInterrupted += c.Old.Interrupted
+		sc.NewInterrupted += c.New.Interrupted
+		if c.Regression {
+			sc.Regressions++
+		}
+		if c.Improvement {
+			sc.Improvements++
+		}
+		if c.Old.Count > 0 && c.New.Count > 0 {
+			logRatios[key] = append(logRatios[key], math.Log1p(c.Delta))
+		}
+	}
+
+	result := make([]SizeComparison, 0, len(sizes))
+	for key, sc := range sizes {
+		if ratios := logRatios[key]; len(ratios) > 0 {
+			total := 0.0
+			for _, r := range ratios {
+				total += r
+			}
+			sc.Delta = math.Expm1(total / float64(len(ratios)))
+		}
+		result = append(result, *sc)
+	}
+	sort.Slice(result, func(a, b int) bool {
+		if result[a].Solver != result[b].Solver {
+			return result[a].Solver < result[b].Solver
+		}
+		return result[a].N < result[b].N
+	})
+	return result
+}
+
+// summarize calcule la médiane et son intervalle de confiance à 95 % par les statistiques d'ordre : les rangs
+// n/2 ± 1.96·sqrt(n)/2 encadrent la médiane (approximation normale de la loi binomiale). Un groupe dont toutes
+// les mesures ont été interrompues n'a pas de temps.
+func summarize(g *group) Summary {
+	sorted := append([]float64(nil), g.times...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n == 0 {
+		return Summary{Interrupted: g.interrupted}
+	}
+
+	s := Summary{Count: n, Interrupted: g.interrupted, Median: time.Duration(median(sorted))}
+	half := 1.96 * math.Sqrt(float64(n)) / 2
+	low := int(math.Floor(float64(n)/2 - half))
+	high := int(math.Ceil(float64(n)/2+half)) - 1
+	if low < 0 {
+		low = 0
+	}
+	if high > n-1 {
+		high = n - 1
+	}
+	s.Low, s.High = time.Duration(sorted[low]), time.Duration(sorted[high])
+	return s
+}
+
+/* median renvoie la médiane de valeurs triées */
+func median(sorted []float64) float64 {
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// MannWhitney calcule la statistique U du test de Mann-Whitney (nombre de paires où la valeur de a dépasse
+// celle de b, les égalités comptant pour moitié) et la p-valeur bilatérale : exacte pour les petits échantillons
+// sans égalité (au plus exactLimit valeurs en tout), sinon par l'approximation normale avec correction des
+// égalités et de continuité. La p-valeur vaut 1 si l'un des échantillons est vide ou si toutes les valeurs sont
+// égales.
+func MannWhitney(a, b []float64) (float64, float64) {
+	n1, n2 := float64(len(a)), float64(len(b))
+	if len(a) == 0 || len(b) == 0 {
+		return 0, 1
+	}
+
+	type sample struct {
+		value float64
+		first bool
+	}
+	all := make([]sample, 0, len(a)+len(b))
+	for _, v := range a {
+		all = append(all, sample{v, true})
+	}
+	for _, v := range b {
+		all = append(all, sample{v, false})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].value < all[j].value })
+
+	// Rangs moyens pour les égalités, et terme de correction somme(t^3 - t)
+	rankSum, ties := 0.0, 0.0
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].value == all[i].value {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if all[k].first {
+				rankSum += rank
+			}
+		}
+		t := float64(j - i)
+		ties += t*t*t - t
+		i = j
+	}
+
+	u := rankSum - n1*(n1+1)/2
+	if ties == 0 && len(all) <= exactLimit {
+		return u, exactMannWhitney(len(a), len(b), u)
+	}
+	n := n1 + n2
+	variance := n1 * n2 / 12 * (n + 1 - ties/(n*(n-1)))
+	if variance <= 0 {
+		return u, 1
+	}
+	z := (math.Abs(u-n1*n2/2) - 0.5) / math.Sqrt(variance)
+	if z < 0 {
+		z = 0
+	}
+	return u, math.Erfc(z / math.Sqrt2)
+}
+
+/* exactLimit est le nombre total de valeurs jusqu'auquel la loi exacte de U est calculée */
+const exactLimit = 40
+
+// exactMannWhitney renvoie la p-valeur bilatérale exacte de U sans égalité : le nombre f(m, n, k) de
+// classements de m et n valeurs de statistique k vérifie f(m, n, k) = f(m-1, n, k-n) + f(m, n-1, k), et
+// P(U <= u) se lit dans la loi cumulée, la loi étant symétrique autour de m·n/2.
+func exactMannWhitney(m, n int, u float64) float64 {
+	// counts[j][k] = f(i, j, k) pour la valeur courante de i, en commençant par i = 0
+	counts := make([][]float64, n+1)
+	for j := range counts {
+		counts[j] = make([]float64, m*n+1)
+		counts[j][0] = 1
+	}
+	for i := 1; i <= m; i++ {
+		next := make([][]float64, n+1)
+		next[0] = make([]float64, m*n+1)
+		next[0][0] = 1
+		for j := 1; j <= n; j++ {
+			next[j] = make([]float64, m*n+1)
+			for k := 0; k <= i*j; k++ {
+				next[j][k] = next[j-1][k]
+				if k >= j {
+					next[j][k] += counts[j][k-j]
+				}
+			}
+		}
+		counts = next
+	}
+
+	tail := math.Min(u, float64(m*n)-u)
+	below, total := 0.0, 0.0
+	for k, c := range counts[n] {
+		total += c
+		if float64(k) <= tail {
+			below += c
+		}
+	}
+	return math.Min(1, 2*below/total)
+}
+
+// WriteComparisons affiche les comparaisons sous forme de tableau, les mesures interrompues (exclues des temps)
+// étant signalées par « +k int. », puis leur résumé par solveur et par taille (CompareSizes), et renvoie
+// ErrRegression s'il y a au moins une régression.
+func WriteComparisons(w io.Writer, comparisons []Comparison) error {
+	fmt.Fprintf(w, "%-22s %-28s %6s %7s %38s %38s %8s %7s\n", "solveur", "famille", "n", "graine", "avant (IC 95 %)", "après (IC 95 %)", "écart", "p")
+	regressions := 0
+	for _, c := range comparisons {
+		flag := ""
+		switch {
+		case c.Regression:
+			flag = "RÉGRESSION"
+			regressions++
+		case c.Improvement:
+			flag = "amélioration"
+		}
+		fmt.Fprintf(w, "%-22s %-28s %6d %7d %38s %38s %+7.1f%% %7.3f %s\n",
+			c.Solver, c.Family, c.N, c.Seed, formatSummary(c.Old), formatSummary(c.New), 100*c.Delta, c.P, flag)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Par solveur et par taille (moyenne géométrique des écarts par instance) :")
+	fmt.Fprintf(w, "%-22s %6s %9s %8s %11s %13s %13s\n", "solveur", "n", "instances", "écart", "régressions", "améliorations", "interruptions")
+	for _, sc := range CompareSizes(comparisons) {
+		fmt.Fprintf(w, "%-22s %6d %9d %+7.1f%% %11d %13d %6d -> %d\n",
+			sc.Solver, sc.N, sc.Groups, 100*sc.Delta, sc.Regressions, sc.Improvements, sc.OldInterrupted, sc.NewInterrupted)
+	}
+
+	if regressions > 0 {
+		return fmt.Errorf("%w: %d of %d groups", ErrRegression, regressions, len(comparisons))
+	}
+	return nil
+}
+
+func formatSummary(s Summary) string {
+	interrupted := ""
+	if s.Interrupted > 0 {
+		interrupted = fmt.Sprintf(" +%d int.", s.Interrupted)
+	}
+	if s.Count == 0 {
+		return "-" + interrupted
+	}
+	round := func(d time.Duration) time.Duration { return d.Round(time.Microsecond) }
+	if s.Median < time.Microsecond {
+		round = func(d time.Duration) time.Duration { return d }
+	}
+	return fmt.Sprintf("%s [%s, %s]%s", round(s.Median), round(s.Low), round(s.High), interrupted)
+}
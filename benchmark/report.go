@@ -0,0 +1,449 @@
+package benchmark
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+/* Dimensions des graphiques, en pixels */
+const (
+	chartWidth   = 760
+	chartHeight  = 380
+	marginLeft   = 70
+	marginRight  = 190
+	marginTop    = 20
+	marginBottom = 50
+)
+
+/* palette est la suite des couleurs des séries, réutilisée cycliquement */
+var palette = []string{
+	"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd",
+	"#8c564b", "#e377c2", "#7f7f7f", "#bcbd22", "#17becf",
+}
+
+type point struct {
+	x, y float64
+}
+
+type series struct {
+	name   string
+	points []point
+}
+
+/* chart est un graphique en lignes ; yMin et yMax fixent l'axe des ordonnées s'ils ne sont pas égaux */
+type chart struct {
+	title, xLabel, yLabel string
+	logY                  bool
+	yMin, yMax            float64
+	series                []series
+}
+
+// WriteReport écrit un rapport HTML autonome (graphiques SVG intégrés, sans feuille de style ni script
+// externe) construit à partir des résultats d'une campagne : temps d'exécution en fonction de n pour chaque
+// solveur, qualité des solutions des algorithmes gloutons et du FPTAS rapportée à l'optimum, qualité et temps du
+// FPTAS en fonction de ε, et taux de succès des attaques par réseau en fonction de la densité. Chaque graphique
+// est suivi du tableau de ses valeurs.
+func WriteReport(w io.Writer, results Results) error {
+	out := bufio.NewWriter(w)
+	env := results.Environment
+
+	fmt.Fprint(out, `<!DOCTYPE html>
+<html lang="fr">
+<head>
+<meta charset="utf-8">
+<title>Rapport de benchmark — The Knapsack Problem</title>
+<style>
+body { font-family: sans-serif; margin: 2em auto; max-width: 980px; color: #222; }
+h1 { font-size: 1.6em; } h2 { font-size: 1.25em; margin-top: 2em; }
+table { border-collapse: collapse; font-size: 0.85em; margin: 0.5em 0; }
+th, td { border: 1px solid #ccc; padding: 0.2em 0.6em; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+svg text { font-family: sans-serif; font-size: 12px; }
+.empty { color: #888; font-style: italic; }
+</style>
+</head>
+<body>
+<h1>Rapport de benchmark — The Knapsack Problem</h1>
+`)
+	fmt.Fprintf(out, "<p>%d mesures, %d attaques. Go %s, %s/%s, %d processeurs, campagne lancée le %s.</p>\n",
+		len(results.Records), len(results.Attacks), html.EscapeString(env.GoVersion), html.EscapeString(env.OS),
+		html.EscapeString(env.Arch), env.CPUs, env.Started.Format("02/01/2006 15:04"))
+
+	sections := []struct {
+		heading, description string
+		chart                chart
+		format               func(float64) string
+	}{
+		{"Temps d'exécution", "Médiane des temps d'exécution de chaque solveur, toutes familles et graines confondues (échelle logarithmique). " + interruptedNote(results.Records),
+			runtimeChart(results.Records), func(v float64) string { return fmt.Sprintf("%.3f", v) }},
+		{"Qualité des approximations", "Moyenne du rapport valeur / optimum des algorithmes gloutons et du FPTAS, sur les instances dont l'optimum est connu.",
+			qualityChart(results.Records), func(v float64) string { return fmt.Sprintf("%.4f", v) }},
+		{"FPTAS : qualité selon ε", "Moyenne du rapport valeur / optimum du FPTAS pour chaque précision ε mesurée (option -epsilons), par taille d'instance. La garantie est un rapport d'au moins 1 - ε.",
+			epsilonQualityChart(results.Records), func(v float64) string { return fmt.Sprintf("%.4f", v) }},
+		{"FPTAS : temps selon ε", "Médiane des temps d'exécution du FPTAS pour chaque précision ε, par taille d'instance (échelle logarithmique), hors exécutions interrompues.",
+			epsilonRuntimeChart(results.Records), func(v float64) string { return fmt.Sprintf("%.3f", v) }},
+		{"Attaques par réseau", "Proportion d'instances de somme de sous-ensemble résolues par l'attaque de faible densité (LLL), selon la densité n / log2(max a<sub>i</sub>). Une attaque interrompue par la limite de temps compte comme un échec.",
+			attackChart(results.Attacks), func(v float64) string { return fmt.Sprintf("%.0f %%", 100*v) }},
+	}
+	for _, s := range sections {
+		fmt.Fprintf(out, "<h2>%s</h2>\n<p>%s</p>\n", s.heading, s.description)
+		if len(s.chart.series) == 0 {
+			fmt.Fprint(out, "<p class=\"empty\">Aucune donnée dans ce fichier de résultats.</p>\n")
+			continue
+		}
+		s.chart.writeSVG(out)
+		s.chart.writeTable(out, s.format)
+	}
+
+	fmt.Fprint(out, "</body>\n</html>\n")
+	return out.Flush()
+}
+
+/* runtimeChart trace la médiane des temps (en millisecondes) de chaque solveur en fonction de n, hors exécutions interrompues */
+func runtimeChart(records []Record) chart {
+	groups := make(map[string]map[int][]float64)
+	for _, r := range records {
+		if r.Error != "" || r.Interrupted {
+			continue
+		}
+		if groups[r.Solver] == nil {
+			groups[r.Solver] = make(map[int][]float64)
+		}
+		groups[r.Solver][r.N] = append(groups[r.Solver][r.N], float64(r.Nanoseconds)/1e6)
+	}
+
+	c := chart{title: "Temps d'exécution en fonction de n", xLabel: "n (nombre d'objets)", yLabel: "temps médian (ms)", logY: true}
+	for solver, sizes := range groups {
+		s := series{name: solver}
+		for n, values := range sizes {
+			sort.Float64s(values)
+			s.points = append(s.points, point{float64(n), median(values)})
+		}
+		c.series = append(c.series, s)
+	}
+	return c.sorted()
+}
+
+/* interruptedNote indique combien d'exécutions de chaque solveur ont été interrompues et exclues des médianes */
+func interruptedNote(records []Record) string {
+	counts := make(map[string]int)
+	for _, r := range records {
+		if r.Error == "" && r.Interrupted {
+			counts[r.Solver]++
+		}
+	}
+	if len(counts) == 0 {
+		return "Aucune exécution n'a été interrompue par la limite de temps."
+	}
+
+	solvers := make([]string, 0, len(counts))
+	for solver, count := range counts {
+		solvers = append(solvers, fmt.Sprintf("%s : %d", html.EscapeString(solver), count))
+	}
+	sort.Strings(solvers)
+	return "Exécutions interrompues par la limite de temps, exclues des médianes : " + strings.Join(solvers, ", ") + "."
+}
+
+/* qualityChart trace le rapport moyen valeur / optimum des approximations en fonction de n */
+func qualityChart(records []Record) chart {
+	type sum struct {
+		total float64
+		count int
+	}
+	groups := make(map[string]map[int]*sum)
+	for _, r := range records {
+		approximation := strings.HasPrefix(r.Solver, "greedy") || strings.HasPrefix(r.Solver, "fptas")
+		if !approximation || r.Error != "" || r.Optimum <= 0 {
+			continue
+		}
+		if groups[r.Solver] == nil {
+			groups[r.Solver] = make(map[int]*sum)
+		}
+		if groups[r.Solver][r.N] == nil {
+			groups[r.Solver][r.N] = &sum{}
+		}
+		groups[r.Solver][r.N].total += float64(r.Value) / float64(r.Optimum)
+		groups[r.Solver][r.N].count++
+	}
+
+	c := chart{title: "Qualité des solutions approchées", xLabel: "n (nombre d'objets)", yLabel: "valeur / optimum", yMin: 1, yMax: 1}
+	for solver, sizes := range groups {
+		s := series{name: solver}
+		for n, g := range sizes {
+			ratio := g.total / float64(g.count)
+			s.points = append(s.points, point{float64(n), ratio})
+			c.yMin = math.Min(c.yMin, ratio)
+		}
+		c.series = append(c.series, s)
+	}
+	// L'axe part d'un multiple de 0,1 sous le plus mauvais rapport, pour que les écarts restent visibles
+	c.yMin = math.Max(0, math.Floor(c.yMin*10-0.5)/10)
+	return c.sorted()
+}
+
+/* fptasRecords regroupe par taille puis par ε les mesures du FPTAS sans réduction qui vérifient keep */
+func fptasRecords(records []Record, keep func(Record) bool) map[int]map[float64][]Record {
+	groups := make(map[int]map[float64][]Record)
+	for _, r := range records {
+		if r.Epsilon <= 0 || r.Error != "" || strings.HasSuffix(r.Solver, "_reduced") || !keep(r) {
+			continue
+		}
+		if groups[r.N] == nil {
+			groups[r.N] = make(map[float64][]Record)
+		}
+		groups[r.N][r.Epsilon] = append(groups[r.N][r.Epsilon], r)
+	}
+	return groups
+}
+
+/* epsilonQualityChart trace le rapport moyen valeur / optimum du FPTAS en fonction de ε, une série par taille */
+func epsilonQualityChart(records []Record) chart {
+	groups := fptasRecords(records, func(r Record) bool { return r.Optimum > 0 })
+
+	c := chart{title: "Qualité du FPTAS en fonction de ε", xLabel: "ε", yLabel: "valeur / optimum", yMin: 1, yMax: 1}
+	for n, epsilons := range groups {
+		s := series{name: fmt.Sprintf("n = %d", n)}
+		for eps, rs := range epsilons {
+			total := 0.0
+			for _, r := range rs {
+				total += float64(r.Value) / float64(r.Optimum)
+			}
+			ratio := total / float64(len(rs))
+			s.points = append(s.points, point{eps, ratio})
+			c.yMin = math.Min(c.yMin, ratio)
+		}
+		c.series = append(c.series, s)
+	}
+	c.yMin = math.Max(0, math.Floor(c.yMin*10-0.5)/10)
+	return c.sorted()
+}
+
+/* epsilonRuntimeChart trace la médiane des temps (en millisecondes) du FPTAS en fonction de ε, une série par taille */
+func epsilonRuntimeChart(records []Record) chart {
+	groups := fptasRecords(records, func(r Record) bool { return !r.Interrupted })
+
+	c := chart{title: "Temps du FPTAS en fonction de ε", xLabel: "ε", yLabel: "temps médian (ms)", logY: true}
+	for n, epsilons := range groups {
+		s := series{name: fmt.Sprintf("n = %d", n)}
+		for eps, rs := range epsilons {
+			values := make([]float64, len(rs))
+			for k, r := range rs {
+				values[k] = float64(r.Nanoseconds) / 1e6
+			}
+			sort.Float64s(values)
+			s.points = append(s.points, point{eps, median(values)})
+		}
+		c.series = append(c.series, s)
+	}
+	return c.sorted()
+}
+
+/* attackChart trace le taux de succès de chaque attaque et de chaque taille en fonction de la densité visée */
+func attackChart(attacks []AttackRecord) chart {
+	type group struct {
+		successes int
+		count     int
+	}
+	groups := make(map[string]map[float64]*group)
+	for _, a := range attacks {
+		if a.Error != "" {
+			continue
+		}
+		name := fmt.Sprintf("%s, n = %d", a.Attack, a.N)
+		if groups[name] == nil {
+			groups[name] = make(map[float64]*group)
+		}
+		g := groups[name][a.Target]
+		if g == nil {
+			g = &group{}
+			groups[name][a.Target] = g
+		}
+		g.count++
+		if a.Success {
+			g.successes++
+		}
+	}
+
+	c := chart{title: "Succès de l'attaque en fonction de la densité", xLabel: "densité", yLabel: "taux de succès", yMin: 0, yMax: 1}
+	for name, densities := range groups {
+		s := series{name: name}
+		for density, g := range densities {
+			s.points = append(s.points, point{density, float64(g.successes) / float64(g.count)})
+		}
+		c.series = append(c.series, s)
+	}
+	return c.sorted()
+}
+
+/* sorted trie les séries par nom et leurs points par abscisse */
+func (c chart) sorted() chart {
+	sort.Slice(c.series, func(a, b int) bool { return c.series[a].name < c.series[b].name })
+	for _, s := range c.series {
+		sort.Slice(s.points, func(a, b int) bool { return s.points[a].x < s.points[b].x })
+	}
+	return c
+}
+
+// bounds renvoie les bornes des deux axes. En échelle logarithmique, les ordonnées sont des puissances de 10
+// qui encadrent les valeurs strictement positives.
+func (c chart) bounds() (xMin, xMax, yMin, yMax float64) {
+	xMin, xMax = math.Inf(1), math.Inf(-1)
+	yMin, yMax = math.Inf(1), math.Inf(-1)
+	for _, s := range c.series {
+		for _, p := range s.points {
+			if c.logY && p.y <= 0 {
+				continue
+			}
+			xMin, xMax = math.Min(xMin, p.x), math.Max(xMax, p.x)
+			yMin, yMax = math.Min(yMin, p.y), math.Max(yMax, p.y)
+		}
+	}
+	if math.IsInf(xMin, 0) {
+		xMin, xMax, yMin, yMax = 0, 1, 0, 1
+	}
+	if c.yMin != c.yMax {
+		yMin, yMax = c.yMin, c.yMax
+	}
+	if c.logY {
+		yMin, yMax = math.Pow(10, math.Floor(math.Log10(yMin))), math.Pow(10, math.Ceil(math.Log10(yMax)))
+	}
+	if xMin == xMax {
+		xMin, xMax = xMin-1, xMax+1
+	}
+	if yMin == yMax {
+		yMin, yMax = yMin-1, yMax+1
+	}
+	return xMin, xMax, yMin, yMax
+}
+
+/* writeSVG dessine le graphique : axes, graduations, une ligne et des points par série, et la légende */
+func (c chart) writeSVG(w io.Writer) {
+	xMin, xMax, yMin, yMax := c.bounds()
+	plotWidth := float64(chartWidth - marginLeft - marginRight)
+	plotHeight := float64(chartHeight - marginTop - marginBottom)
+	scaleX := func(x float64) float64 { return marginLeft + (x-xMin)/(xMax-xMin)*plotWidth }
+	scaleY := func(y float64) float64 {
+		if c.logY {
+			return marginTop + (1-(math.Log10(y)-math.Log10(yMin))/(math.Log10(yMax)-math.Log10(yMin)))*plotHeight
+		}
+		return marginTop + (1-(y-yMin)/(yMax-yMin))*plotHeight
+	}
+
+	fmt.Fprintf(w, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" role=\"img\">\n",
+		chartWidth, chartHeight, chartWidth, chartHeight)
+	fmt.Fprintf(w, "<title>%s</title>\n", html.EscapeString(c.title))
+
+	// Graduations et grille
+	for _, x := range ticks(xMin, xMax) {
+		fmt.Fprintf(w, "<line x1=\"%.1f\" y1=\"%d\" x2=\"%.1f\" y2=\"%.1f\" stroke=\"#eee\"/>\n", scaleX(x), marginTop, scaleX(x), marginTop+plotHeight)
+		fmt.Fprintf(w, "<text x=\"%.1f\" y=\"%.1f\" text-anchor=\"middle\">%s</text>\n", scaleX(x), marginTop+plotHeight+16, formatTick(x))
+	}
+	yTicks := ticks(yMin, yMax)
+	if c.logY {
+		yTicks = nil
+		for y := yMin; y <= yMax*1.0001; y *= 10 {
+			yTicks = append(yTicks, y)
+		}
+	}
+	for _, y := range yTicks {
+		fmt.Fprintf(w, "<line x1=\"%d\" y1=\"%.1f\" x2=\"%.1f\" y2=\"%.1f\" stroke=\"#eee\"/>\n", marginLeft, scaleY(y), marginLeft+plotWidth, scaleY(y))
+		fmt.Fprintf(w, "<text x=\"%d\" y=\"%.1f\" text-anchor=\"end\">%s</text>\n", marginLeft-6, scaleY(y)+4, formatTick(y))
+	}
+
+	// Axes et titres des axes
+	fmt.Fprintf(w, "<rect x=\"%d\" y=\"%d\" width=\"%.1f\" height=\"%.1f\" fill=\"none\" stroke=\"#444\"/>\n", marginLeft, marginTop, plotWidth, plotHeight)
+	fmt.Fprintf(w, "<text x=\"%.1f\" y=\"%d\" text-anchor=\"middle\">%s</text>\n", marginLeft+plotWidth/2, chartHeight-10, html.EscapeString(c.xLabel))
+	fmt.Fprintf(w, "<text transform=\"translate(16 %.1f) rotate(-90)\" text-anchor=\"middle\">%s</text>\n", marginTop+plotHeight/2, html.EscapeString(c.yLabel))
+
+	for k, s := range c.series {
+		color := palette[k%len(palette)]
+		var path []string
+		for _, p := range s.points {
+			if c.logY && p.y <= 0 {
+				continue
+			}
+			path = append(path, fmt.Sprintf("%.1f,%.1f", scaleX(p.x), scaleY(p.y)))
+		}
+		fmt.Fprintf(w, "<polyline points=\"%s\" fill=\"none\" stroke=\"%s\" stroke-width=\"2\"/>\n", strings.Join(path, " "), color)
+		for _, p := range s.points {
+			if c.logY && p.y <= 0 {
+				continue
+			}
+			fmt.Fprintf(w, "<circle cx=\"%.1f\" cy=\"%.1f\" r=\"3\" fill=\"%s\"><title>%s : %s ; %s</title></circle>\n",
+				scaleX(p.x), scaleY(p.y), color, html.EscapeString(s.name), formatTick(p.x), formatTick(p.y))
+		}
+
+		legendY := marginTop + 10 + 18*k
+		fmt.Fprintf(w, "<rect x=\"%.1f\" y=\"%d\" width=\"12\" height=\"12\" fill=\"%s\"/>\n", marginLeft+plotWidth+14, legendY-10, color)
+		fmt.Fprintf(w, "<text x=\"%.1f\" y=\"%d\">%s</text>\n", marginLeft+plotWidth+32, legendY, html.EscapeString(s.name))
+	}
+	fmt.Fprint(w, "</svg>\n")
+}
+
+/* writeTable écrit les valeurs du graphique sous forme de tableau, une ligne par série et une colonne par abscisse */
+func (c chart) writeTable(w io.Writer, format func(float64) string) {
+	var xs []float64
+	seen := make(map[string]bool)
+	for _, s := range c.series {
+		for _, p := range s.points {
+			if key := formatTick(p.x); !seen[key] {
+				seen[key] = true
+				xs = append(xs, p.x)
+			}
+		}
+	}
+	sort.Float64s(xs)
+
+	fmt.Fprintf(w, "<details><summary>Valeurs (%s)</summary>\n<table>\n<tr><th>%s</th>", html.EscapeString(c.yLabel), html.EscapeString(c.xLabel))
+	for _, x := range xs {
+		fmt.Fprintf(w, "<th>%s</th>", formatTick(x))
+	}
+	fmt.Fprint(w, "</tr>\n")
+	for _, s := range c.series {
+		values := make(map[string]float64)
+		for _, p := range s.points {
+			values[formatTick(p.x)] = p.y
+		}
+		fmt.Fprintf(w, "<tr><td>%s</td>", html.EscapeString(s.name))
+		for _, x := range xs {
+			if v, ok := values[formatTick(x)]; ok {
+				fmt.Fprintf(w, "<td>%s</td>", format(v))
+			} else {
+				fmt.Fprint(w, "<td></td>")
+			}
+		}
+		fmt.Fprint(w, "</tr>\n")
+	}
+	fmt.Fprint(w, "</table>\n</details>\n")
+}
+
+/* ticks renvoie des graduations régulières de pas 1, 2 ou 5 fois une puissance de 10, environ cinq sur l'intervalle */
+func ticks(min, max float64) []float64 {
+	raw := (max - min) / 5
+	step := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, factor := range []float64{1, 2, 5, 10} {
+		if step*factor >= raw {
+			step *= factor
+			break
+		}
+	}
+
+	var values []float64
+	for v := math.Ceil(min/step) * step; v <= max+step*1e-9; v += step {
+		values = append(values, v)
+	}
+	return values
+}
+
+/* formatTick écrit une valeur de graduation sans zéros inutiles */
+func formatTick(v float64) string {
+	if math.Abs(v) >= 1e4 || (v != 0 && math.Abs(v) < 1e-3) {
+		return fmt.Sprintf("%.0e", v)
+	}
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.3f", v), "0"), ".")
+}
@@ -0,0 +1,144 @@
+package benchmark
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"../create_data"
+)
+
+/* csvHeader est l'en-tête des fichiers CSV, une colonne par champ de Record */
+var csvHeader = []string{
+	"solver", "family", "n", "seed", "repetition", "capacity", "value", "bound", "optimum",
+	"optimal", "interrupted", "nodes", "fixed", "epsilon", "ns", "allocs", "bytes", "error",
+}
+
+/* isCSV indique si le fichier doit être lu ou écrit en CSV (extension .csv), plutôt qu'en JSON */
+func isCSV(filename string) bool {
+	return strings.EqualFold(filepath.Ext(filename), ".csv")
+}
+
+/* WriteFile écrit les résultats en CSV si l'extension est .csv, en JSON sinon */
+func WriteFile(filename string, results Results) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	if isCSV(filename) {
+		err = WriteCSV(file, results)
+	} else {
+		err = WriteJSON(file, results)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+/* ReadFile lit des résultats écrits par WriteFile ; un fichier CSV ne contient que les mesures, sans les attaques */
+func ReadFile(filename string) (Results, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return Results{}, err
+	}
+	defer file.Close()
+
+	if isCSV(filename) {
+		return ReadCSV(file)
+	}
+	return ReadJSON(file)
+}
+
+func WriteJSON(w io.Writer, results Results) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(results)
+}
+
+func ReadJSON(r io.Reader) (Results, error) {
+	var results Results
+	if err := json.NewDecoder(r).Decode(&results); err != nil {
+		return Results{}, err
+	}
+	return results, nil
+}
+
+func WriteCSV(w io.Writer, results Results) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, r := range results.Records {
+		row := []string{
+			r.Solver, string(r.Family), strconv.Itoa(r.N), strconv.FormatInt(r.Seed, 10),
+			strconv.Itoa(r.Repetition), strconv.Itoa(r.Capacity), strconv.Itoa(r.Value),
+			strconv.Itoa(r.Bound), strconv.Itoa(r.Optimum), strconv.FormatBool(r.Optimal),
+			strconv.FormatBool(r.Interrupted), strconv.FormatInt(r.Nodes, 10), strconv.Itoa(r.Fixed),
+			strconv.FormatFloat(r.Epsilon, 'g', -1, 64), strconv.FormatInt(r.Nanoseconds, 10),
+			strconv.FormatUint(r.Allocs, 10), strconv.FormatUint(r.Bytes, 10), r.Error,
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func ReadCSV(r io.Reader) (Results, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return Results{}, err
+	}
+	if len(rows) == 0 || strings.Join(rows[0], ",") != strings.Join(csvHeader, ",") {
+		return Results{}, fmt.Errorf("Unexpected CSV header, expected %s", strings.Join(csvHeader, ","))
+	}
+
+	results := Results{Records: make([]Record, 0, len(rows)-1)}
+	for line, row := range rows[1:] {
+		var r Record
+		var ints [7]int
+		var int64s [3]int64
+		var uints [2]uint64
+		var bools [2]bool
+
+		r.Solver, r.Family, r.Error = row[0], create_data.Family(row[1]), row[17]
+		for k, col := range []int{2, 4, 5, 6, 7, 8, 12} {
+			if ints[k], err = strconv.Atoi(row[col]); err != nil {
+				return Results{}, fmt.Errorf("Line %d: %v", line+2, err)
+			}
+		}
+		for k, col := range []int{3, 11, 14} {
+			if int64s[k], err = strconv.ParseInt(row[col], 10, 64); err != nil {
+				return Results{}, fmt.Errorf("Line %d: %v", line+2, err)
+			}
+		}
+		for k, col := range []int{15, 16} {
+			if uints[k], err = strconv.ParseUint(row[col], 10, 64); err != nil {
+				return Results{}, fmt.Errorf("Line %d: %v", line+2, err)
+			}
+		}
+		if r.Epsilon, err = strconv.ParseFloat(row[13], 64); err != nil {
+			return Results{}, fmt.Errorf("Line %d: %v", line+2, err)
+		}
+		for k, col := range []int{9, 10} {
+			if bools[k], err = strconv.ParseBool(row[col]); err != nil {
+				return Results{}, fmt.Errorf("Line %d: %v", line+2, err)
+			}
+		}
+
+		r.N, r.Repetition, r.Capacity, r.Value, r.Bound, r.Optimum, r.Fixed = ints[0], ints[1], ints[2], ints[3], ints[4], ints[5], ints[6]
+		r.Seed, r.Nodes, r.Nanoseconds = int64s[0], int64s[1], int64s[2]
+		r.Allocs, r.Bytes = uints[0], uints[1]
+		r.Optimal, r.Interrupted = bools[0], bools[1]
+		results.Records = append(results.Records, r)
+	}
+	return results, nil
+}
@@ -0,0 +1,141 @@
+package bounded_knapsack
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"../algo_prog_dynamique"
+	"../algorithme_glouton"
+	"../common"
+)
+
+// Bounded résout le sac à dos borné, où l'objet i peut être pris jusqu'à objects[i].Copies() fois.
+// Chaque objet est découpé en paquets de 1, 2, 4, ... exemplaires (découpage binaire), ce qui ramène le
+// problème à un sac à dos 0/1 de O(n log q) objets résolu par programmation dynamique.
+// Retourne la valeur optimale, le nombre d'exemplaires choisis de chaque objet et si le contexte a interrompu
+// la résolution (les paquets sont alors choisis par l'algorithme glouton).
+func Bounded(ctx context.Context, objects []common.Objects, capacity int) (int, []int, bool, error) {
+	if capacity < 0 {
+		return 0, nil, false, fmt.Errorf("Invalid capacity %d", capacity)
+	}
+
+	var parts []common.Objects
+	var owner, size []int
+	for i, obj := range objects {
+		remaining := obj.Copies()
+		for k := 1; remaining > 0; k *= 2 {
+			if k > remaining {
+				k = remaining
+			}
+			parts = append(parts, common.Objects{Weight: k * obj.Weight, Value: k * obj.Value})
+			owner = append(owner, i)
+			size = append(size, k)
+			remaining -= k
+		}
+	}
+
+	value, indices, _, interrupted := algo_prog_dynamique.KnapsackLowMemoryContext(ctx, parts, capacity)
+	if interrupted {
+		indices = algorithme_glouton.KnapsackHalf(parts, capacity)
+		value = 0
+		for _, p := range indices {
+			value += parts[p].Value
+		}
+	}
+
+	counts := make([]int, len(objects))
+	for _, p := range indices {
+		counts[owner[p]] += size[p]
+	}
+	return value, counts, interrupted, nil
+}
+
+// Unbounded résout le sac à dos non borné, où chaque objet peut être pris autant de fois que voulu, par une
+// programmation dynamique en O(n·capacity) : best[c] est la meilleure valeur de poids au plus c et last[c]
+// le dernier objet ajouté pour l'atteindre, ce qui suffit à reconstruire la solution.
+// Retourne la valeur optimale, le nombre d'exemplaires choisis de chaque objet et si le contexte a interrompu
+// la résolution : la solution renvoyée est alors optimale pour la plus grande capacité déjà traitée.
+func Unbounded(ctx context.Context, objects []common.Objects, capacity int) (int, []int, bool, error) {
+	if capacity < 0 {
+		return 0, nil, false, fmt.Errorf("Invalid capacity %d", capacity)
+	}
+	for i, obj := range objects {
+		if obj.Weight <= 0 && obj.Value > 0 {
+			return 0, nil, false, fmt.Errorf("Object %d has weight %d and positive value: the unbounded knapsack has no optimum", i, obj.Weight)
+		}
+	}
+
+	best := make([]int, capacity+1)
+	last := make([]int, capacity+1)
+	for c := range last {
+		last[c] = -1
+	}
+
+	poll := common.NewPoll(ctx)
+	reached := 0
+	for c := 1; c <= capacity && !poll.Done(); c++ {
+		reached = c
+		// Sans objet ajouté, la meilleure valeur de poids au plus c est celle de poids au plus c-1
+		best[c] = best[c-1]
+		for i, obj := range objects {
+			if obj.Value > 0 && obj.Weight > 0 && obj.Weight <= c && best[c-obj.Weight]+obj.Value > best[c] {
+				best[c] = best[c-obj.Weight] + obj.Value
+				last[c] = i
+			}
+		}
+	}
+
+	counts := make([]int, len(objects))
+	for c := reached; c > 0; {
+		if last[c] < 0 {
+			c--
+			continue
+		}
+		counts[last[c]]++
+		c -= objects[last[c]].Weight
+	}
+	return best[reached], counts, reached < capacity, nil
+}
+
+/* BoundedSolver adapte le sac à dos borné à l'interface common.Solver */
+type BoundedSolver struct{}
+
+/* UnboundedSolver adapte le sac à dos non borné à l'interface common.Solver */
+type UnboundedSolver struct{}
+
+func init() {
+	common.Register(BoundedSolver{})
+	common.RegisterVariant(UnboundedSolver{})
+}
+
+func (BoundedSolver) Name() string {
+	return "bounded"
+}
+
+func (BoundedSolver) Solve(ctx context.Context, inst common.Instance) (common.Result, error) {
+	return solveCounts(ctx, inst, Bounded)
+}
+
+func (UnboundedSolver) Name() string {
+	return "unbounded"
+}
+
+func (UnboundedSolver) Solve(ctx context.Context, inst common.Instance) (common.Result, error) {
+	return solveCounts(ctx, inst, Unbounded)
+}
+
+func solveCounts(ctx context.Context, inst common.Instance, knapsack func(context.Context, []common.Objects, int) (int, []int, bool, error)) (common.Result, error) {
+	startTime := time.Now()
+	_, counts, interrupted, err := knapsack(ctx, inst.Objects, inst.Capacity)
+	if err != nil {
+		return common.Result{}, err
+	}
+
+	res := common.NewCountResult(inst, counts, true)
+	if interrupted {
+		res.Interrupt(0)
+	}
+	res.Stats.Duration = time.Since(startTime)
+	return res, nil
+}
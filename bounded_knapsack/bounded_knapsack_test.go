@@ -0,0 +1,129 @@
+package bounded_knapsack_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"../bounded_knapsack"
+	"../common"
+)
+
+/* bruteForce énumère tous les nombres d'exemplaires possibles de chaque objet */
+func bruteForce(objects []common.Objects, capacity int) int {
+	best := 0
+	var explore func(i, weight, value int)
+	explore = func(i, weight, value int) {
+		if i == len(objects) {
+			if value > best {
+				best = value
+			}
+			return
+		}
+		for k := 0; k <= objects[i].Copies() && weight+k*objects[i].Weight <= capacity; k++ {
+			explore(i+1, weight+k*objects[i].Weight, value+k*objects[i].Value)
+		}
+	}
+	explore(0, 0, 0)
+	return best
+}
+
+func randomObjects(random *rand.Rand, n int) []common.Objects {
+	objects := make([]common.Objects, n)
+	for i := range objects {
+		objects[i] = common.Objects{Weight: 1 + random.Intn(20), Value: 1 + random.Intn(30), Quantity: random.Intn(5)}
+	}
+	return objects
+}
+
+/* check vérifie que les exemplaires choisis respectent la capacité et donnent la valeur annoncée */
+func check(t *testing.T, name string, objects []common.Objects, capacity, value int, counts []int, limited bool) {
+	t.Helper()
+	weight, total := 0, 0
+	for i, count := range counts {
+		if count < 0 || (limited && count > objects[i].Copies()) {
+			t.Fatalf("%s: %d copies of object %d (available: %d)", name, count, i, objects[i].Copies())
+		}
+		weight += count * objects[i].Weight
+		total += count * objects[i].Value
+	}
+	if weight > capacity || total != value {
+		t.Errorf("%s: weight %d for capacity %d, value %d announced %d", name, weight, capacity, total, value)
+	}
+}
+
+func TestBoundedAgainstBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 100; trial++ {
+		objects := randomObjects(random, random.Intn(7))
+		capacity := random.Intn(80)
+		value, counts, interrupted, err := bounded_knapsack.Bounded(context.Background(), objects, capacity)
+		if err != nil || interrupted {
+			t.Fatalf("Trial %d: interrupted %t, %v", trial, interrupted, err)
+		}
+		check(t, "Bounded", objects, capacity, value, counts, true)
+		if optimum := bruteForce(objects, capacity); value != optimum {
+			t.Errorf("Trial %d: value %d, optimum %d", trial, value, optimum)
+		}
+	}
+}
+
+func TestUnboundedDominatesBounded(t *testing.T) {
+	random := rand.New(rand.NewSource(2))
+	for trial := 0; trial < 100; trial++ {
+		objects := randomObjects(random, 1+random.Intn(6))
+		capacity := random.Intn(80)
+		bounded, _, _, err := bounded_knapsack.Bounded(context.Background(), objects, capacity)
+		if err != nil {
+			t.Fatal(err)
+		}
+		value, counts, interrupted, err := bounded_knapsack.Unbounded(context.Background(), objects, capacity)
+		if err != nil || interrupted {
+			t.Fatalf("Trial %d: interrupted %t, %v", trial, interrupted, err)
+		}
+		check(t, "Unbounded", objects, capacity, value, counts, false)
+
+		// Avec autant d'exemplaires que la capacité en permet, le sac borné devient le sac non borné
+		unlimited := make([]common.Objects, len(objects))
+		for i, obj := range objects {
+			obj.Quantity = capacity/obj.Weight + 1
+			unlimited[i] = obj
+		}
+		if optimum := bruteForce(unlimited, capacity); value != optimum || value < bounded {
+			t.Errorf("Trial %d: unbounded value %d, optimum %d, bounded value %d", trial, value, optimum, bounded)
+		}
+	}
+}
+
+func TestInterrupted(t *testing.T) {
+	random := rand.New(rand.NewSource(3))
+	objects := randomObjects(random, 50)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	value, counts, interrupted, err := bounded_knapsack.Bounded(ctx, objects, 10000)
+	if err != nil || !interrupted {
+		t.Fatalf("Bounded: interrupted %t, %v", interrupted, err)
+	}
+	check(t, "Bounded", objects, 10000, value, counts, true)
+
+	value, counts, interrupted, err = bounded_knapsack.Unbounded(ctx, objects, 10000)
+	if err != nil || !interrupted {
+		t.Fatalf("Unbounded: interrupted %t, %v", interrupted, err)
+	}
+	check(t, "Unbounded", objects, 10000, value, counts, false)
+}
+
+func TestInvalidInstances(t *testing.T) {
+	objects := []common.Objects{{Weight: 2, Value: 3}}
+	if _, _, _, err := bounded_knapsack.Bounded(context.Background(), objects, -1); err == nil {
+		t.Error("Bounded: expected an error for a negative capacity")
+	}
+	if _, _, _, err := bounded_knapsack.Unbounded(context.Background(), objects, -1); err == nil {
+		t.Error("Unbounded: expected an error for a negative capacity")
+	}
+	free := []common.Objects{{Weight: 0, Value: 1}}
+	if _, _, _, err := bounded_knapsack.Unbounded(context.Background(), free, 10); err == nil {
+		t.Error("Unbounded: expected an error for a weightless object of positive value")
+	}
+}
@@ -0,0 +1,210 @@
+package branch_and_bound
+
+import (
+	"context"
+	"time"
+
+	"../algorithme_glouton"
+	"../common"
+)
+
+/* Bound désigne la borne supérieure utilisée pour élaguer l'arbre de recherche */
+type Bound int
+
+const (
+	Dantzig      Bound = iota // relaxation linéaire (borne de Dantzig)
+	MartelloToth              // borne U2 de Martello-Toth, toujours inférieure ou égale à celle de Dantzig
+)
+
+/* search contient l'état de la recherche en profondeur sur les objets triés par rapport valeur/poids */
+type search struct {
+	weights  []int
+	values   []int
+	capacity int
+	bound    Bound
+
+	taken     []bool
+	best      int
+	bestTaken []bool
+	nodes     int64
+	poll      *common.Poll
+	leaves    []common.Leaf // feuilles de l'arbre exploré, nil au-delà de maxLeaves
+}
+
+/* maxLeaves est le nombre maximal de feuilles conservées pour le certificat d'optimalité */
+const maxLeaves = 1 << 16
+
+// Knapsack résout le problème du sac à dos par séparation et évaluation et retourne la meilleure valeur, les
+// indices des objets choisis, le nombre de noeuds explorés, si la recherche a été interrompue par le contexte
+// (la solution renvoyée est alors la meilleure trouvée, sans garantie d'optimalité) et, sauf interruption ou
+// arbre de plus de maxLeaves feuilles, le certificat formé par les feuilles de l'arbre exploré.
+func Knapsack(ctx context.Context, objects []common.Objects, capacity int, bound Bound) (int, []int, int64, bool, *common.Certificate) {
+	// Les objets sont explorés dans l'ordre du rapport valeur/poids décroissant ;
+	// ceux de valeur nulle ou négative n'améliorent jamais la solution et sont écartés
+	order := make([]int, 0, len(objects))
+	for _, i := range common.RatioOrder(objects) {
+		if objects[i].Value > 0 {
+			order = append(order, i)
+		}
+	}
+	n := len(order)
+
+	s := &search{
+		weights:   make([]int, n),
+		values:    make([]int, n),
+		capacity:  capacity,
+		bound:     bound,
+		taken:     make([]bool, n),
+		bestTaken: make([]bool, n),
+		poll:      common.NewPoll(ctx),
+		leaves:    make([]common.Leaf, 0),
+	}
+	for k, i := range order {
+		s.weights[k] = objects[i].Weight
+		s.values[k] = objects[i].Value
+	}
+
+	s.branch(0, 0, 0)
+	s.poll.Finish(common.Progress{Solver: "branch_and_bound", Nodes: s.nodes, Best: s.best})
+
+	indices := make([]int, 0)
+	for k, taken := range s.bestTaken {
+		if taken {
+			indices = append(indices, order[k])
+		}
+	}
+
+	if s.poll.Stopped() || s.leaves == nil {
+		return s.best, indices, s.nodes, s.poll.Stopped(), nil
+	}
+	return s.best, indices, s.nodes, false, &common.Certificate{Kind: common.SearchTree, Order: order, Leaves: s.leaves}
+}
+
+/* branch explore le noeud où les k premiers objets sont fixés */
+func (s *search) branch(k, weight, value int) {
+	if s.poll.Done() {
+		return
+	}
+	if s.poll.Due() {
+		s.poll.Report(common.Progress{Solver: "branch_and_bound", Nodes: s.nodes, Best: s.best})
+	}
+	s.nodes++
+
+	// Toute solution partielle réalisable est une solution du problème
+	if value > s.best {
+		s.best = value
+		copy(s.bestTaken, s.taken)
+	}
+
+	if k == len(s.weights) || s.upperBound(k, weight, value) <= s.best {
+		s.addLeaf(k)
+		return
+	}
+
+	s.taken[k] = true
+	if weight+s.weights[k] <= s.capacity {
+		s.branch(k+1, weight+s.weights[k], value+s.values[k])
+	} else {
+		// Le fils où l'objet est pris est irréalisable : c'est une feuille
+		s.addLeaf(k + 1)
+	}
+	s.taken[k] = false
+
+	s.branch(k+1, weight, value)
+}
+
+/* addLeaf enregistre le noeud courant de profondeur depth comme feuille de l'arbre, jusqu'à maxLeaves feuilles */
+func (s *search) addLeaf(depth int) {
+	if s.leaves == nil {
+		return
+	}
+	if len(s.leaves) == maxLeaves {
+		s.leaves = nil
+		return
+	}
+
+	leaf := common.Leaf{Depth: depth, Taken: make([]int, 0)}
+	for k := 0; k < depth; k++ {
+		if s.taken[k] {
+			leaf.Taken = append(leaf.Taken, k)
+		}
+	}
+	s.leaves = append(s.leaves, leaf)
+}
+
+/* upperBound calcule une borne supérieure de la valeur atteignable depuis le noeud k */
+func (s *search) upperBound(k, weight, value int) int {
+	n := len(s.weights)
+	remaining := s.capacity - weight
+
+	// Remplir le sac jusqu'à l'objet critique b
+	b := k
+	for b < n && s.weights[b] <= remaining {
+		remaining -= s.weights[b]
+		value += s.values[b]
+		b++
+	}
+	if b == n {
+		return value
+	}
+
+	dantzig := value + remaining*s.values[b]/s.weights[b]
+	if s.bound == Dantzig {
+		return dantzig
+	}
+
+	// U0 : l'objet critique est exclu, le reste est rempli au rapport de l'objet suivant
+	u0 := value
+	if b+1 < n {
+		u0 += remaining * s.values[b+1] / s.weights[b+1]
+	}
+
+	// Si l'objet critique est le premier objet libre, il ne rentre pas : U0 suffit
+	if b == k {
+		return u0
+	}
+
+	// U1 : l'objet critique est inclus, en retirant une fraction de l'objet précédent
+	// (si cet objet est de poids nul, on se rabat sur la borne de Dantzig)
+	if s.weights[b-1] == 0 {
+		return dantzig
+	}
+	excess := s.weights[b] - remaining
+	u1 := value + s.values[b] - (excess*s.values[b-1]+s.weights[b-1]-1)/s.weights[b-1]
+	if u1 > u0 {
+		return u1
+	}
+	return u0
+}
+
+/* Solver adapte la séparation et évaluation à l'interface common.Solver */
+type Solver struct {
+	Bound Bound
+}
+
+func init() {
+	common.Register(Solver{Bound: Dantzig})
+	common.Register(Solver{Bound: MartelloToth})
+}
+
+func (s Solver) Name() string {
+	if s.Bound == MartelloToth {
+		return "branch_and_bound_mt"
+	}
+	return "branch_and_bound"
+}
+
+func (s Solver) Solve(ctx context.Context, inst common.Instance) (common.Result, error) {
+	startTime := time.Now()
+	_, indices, nodes, interrupted, tree := Knapsack(ctx, inst.Objects, inst.Capacity, s.Bound)
+
+	res := common.NewResult(inst, indices, true)
+	if interrupted {
+		res.Interrupt(algorithme_glouton.FractionalKnapsack(inst.Objects, inst.Capacity).Bound)
+	} else if res.Certificate = common.DualCertificate(inst, res.Value); res.Certificate == nil {
+		res.Certificate = tree
+	}
+	res.Stats.Nodes = nodes
+	res.Stats.Duration = time.Since(startTime)
+	return res, nil
+}
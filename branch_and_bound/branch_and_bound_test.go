@@ -0,0 +1,78 @@
+package branch_and_bound_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"../algo_prog_dynamique"
+	"../branch_and_bound"
+	"../common"
+	"../create_data"
+	"../verification"
+)
+
+func TestAgainstDynamicProgramming(t *testing.T) {
+	for _, family := range create_data.Families() {
+		for _, n := range []int{0, 1, 10, 30} {
+			inst, _, err := create_data.Generate(create_data.Config{Family: family, N: n, Range: 100, Seed: int64(n + 1)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			optimum := algo_prog_dynamique.KnapsackValue(inst.Objects, inst.Capacity)
+			for _, bound := range []branch_and_bound.Bound{branch_and_bound.Dantzig, branch_and_bound.MartelloToth} {
+				solver := branch_and_bound.Solver{Bound: bound}
+				res, err := solver.Solve(context.Background(), inst)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !res.Optimal || res.Value != optimum {
+					t.Errorf("%s, %s n=%d: value %d (optimal: %t), optimum %d", solver.Name(), family, n, res.Value, res.Optimal, optimum)
+				}
+				if err := verification.VerifyCertificate(inst, res.Value, res.Certificate); err != nil {
+					t.Errorf("%s, %s n=%d: %v", solver.Name(), family, n, err)
+				}
+			}
+		}
+	}
+}
+
+func TestMartelloTothExploresLess(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 20; trial++ {
+		inst := common.Instance{Objects: make([]common.Objects, 25)}
+		for i := range inst.Objects {
+			w := 1 + random.Intn(100)
+			inst.Objects[i] = common.Objects{Weight: w, Value: w + 10}
+			inst.Capacity += w
+		}
+		inst.Capacity /= 2
+
+		dantzig, _, dantzigNodes, _, _ := branch_and_bound.Knapsack(context.Background(), inst.Objects, inst.Capacity, branch_and_bound.Dantzig)
+		mt, _, mtNodes, _, _ := branch_and_bound.Knapsack(context.Background(), inst.Objects, inst.Capacity, branch_and_bound.MartelloToth)
+		if dantzig != mt || mtNodes > dantzigNodes {
+			t.Errorf("Trial %d: Dantzig %d in %d nodes, Martello-Toth %d in %d nodes", trial, dantzig, dantzigNodes, mt, mtNodes)
+		}
+	}
+}
+
+func TestInterrupted(t *testing.T) {
+	inst, _, err := create_data.Generate(create_data.Config{Family: create_data.StronglyCorrelated, N: 100, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	res, err := branch_and_bound.Solver{}.Solve(ctx, inst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := verification.Check(inst, res); err != nil {
+		t.Error(err)
+	}
+	optimum := algo_prog_dynamique.KnapsackValue(inst.Objects, inst.Capacity)
+	if res.Optimal || !res.Stats.Interrupted || res.Bound < optimum || res.Certificate != nil {
+		t.Errorf("Expected an interrupted, uncertified result with a valid bound: bound %d, optimum %d, %+v", res.Bound, optimum, res.Stats)
+	}
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"./benchmark"
+	"./create_data"
+	"./tools"
+)
+
+/* commands associe à chaque sous-commande la fonction qui l'exécute avec ses arguments */
+var commands = map[string]func(args []string) error{
+	"bench":   runBench,
+	"compare": runCompare,
+	"report":  runReport,
+	"mkp":     runMultipleKnapsack,
+	"bins":    runBinPacking,
+}
+
+/* runCommand exécute une sous-commande */
+func runCommand(name string, args []string) error {
+	command, ok := commands[name]
+	if !ok {
+		names := make([]string, 0, len(commands))
+		for name := range commands {
+			names = append(names, name)
+		}
+		return fmt.Errorf("Unknown command %q (available: %s)", name, strings.Join(names, ", "))
+	}
+	return command(args)
+}
+
+/* runBench exécute une campagne de mesures et l'écrit en JSON ou en CSV selon l'extension du fichier de sortie */
+func runBench(args []string) error {
+	flags := flag.NewFlagSet("bench", flag.ContinueOnError)
+	solvers := flags.String("solvers", "", "solveurs séparés par des virgules (tous par défaut)")
+	families := flags.String("families", "", "familles d'instances séparées par des virgules (toutes par défaut)")
+	sizes := flags.String("sizes", "10,20,50,100", "nombres d'objets séparés par des virgules")
+	seeds := flags.String("seeds", "1,2,3", "graines séparées par des virgules")
+	epsilons := flags.String("epsilons", "", "précisions du FPTAS mesurées en plus des solveurs, séparées par des virgules (solveurs fptas_<ε>)")
+	var cfg benchmark.Config
+	flags.IntVar(&cfg.Range, "range", 1000, "les poids sont tirés dans [1, range]")
+	flags.IntVar(&cfg.Repetitions, "reps", 5, "exécutions de chaque solveur sur chaque instance")
+	flags.DurationVar(&cfg.Timeout, "timeout", benchmark.DefaultTimeout, "durée maximale d'une exécution")
+	flags.StringVar(&cfg.Reference, "reference", "minknap", "solveur exact qui fournit l'optimum")
+	flags.BoolVar(&cfg.Reduce, "reduce", false, "mesurer aussi chaque solveur précédé de la réduction (suffixe _reduced)")
+	output := flags.String("o", "results.json", "fichier de sortie (.json ou .csv)")
+	attacks := flags.Bool("attacks", false, "mesurer aussi le taux de succès de l'attaque par réseau selon la densité (JSON seulement)")
+	attackSizes := flags.String("attack-sizes", "16,32", "nombres de poids des instances attaquées, séparés par des virgules")
+	var attackCfg benchmark.AttackConfig
+	flags.IntVar(&attackCfg.Trials, "attack-trials", 10, "instances attaquées par taille et par densité")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg.Solvers = splitList(*solvers)
+	for _, family := range splitList(*families) {
+		cfg.Families = append(cfg.Families, create_data.Family(family))
+	}
+	var err error
+	if cfg.Sizes, err = parseInts(*sizes); err != nil {
+		return err
+	}
+	seedList, err := parseInts(*seeds)
+	if err != nil {
+		return err
+	}
+	for _, seed := range seedList {
+		cfg.Seeds = append(cfg.Seeds, int64(seed))
+	}
+	if cfg.Epsilons, err = parseFloats(*epsilons); err != nil {
+		return err
+	}
+
+	if attackCfg.Sizes, err = parseInts(*attackSizes); err != nil {
+		return err
+	}
+
+	results, err := benchmark.Run(context.Background(), cfg, func(r benchmark.Record) {
+		fmt.Fprintf(os.Stderr, "\r%-100s", fmt.Sprintf("%s %s n=%d graine=%d : %s", r.Solver, r.Family, r.N, r.Seed, r.Duration()))
+	})
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	if *attacks {
+		results.Attacks, err = benchmark.RunAttacks(context.Background(), attackCfg, func(a benchmark.AttackRecord) {
+			fmt.Fprintf(os.Stderr, "\r%-100s", fmt.Sprintf("%s n=%d densité=%.2f : %t", a.Attack, a.N, a.Density, a.Success))
+		})
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := benchmark.WriteFile(*output, results); err != nil {
+		return err
+	}
+	fmt.Printf("%d mesures écrites dans %s\n", len(results.Records), *output)
+	return nil
+}
+
+// runCompare compare deux fichiers de résultats (avant, après) groupe par groupe et échoue si au moins une
+// régression significative dépasse le seuil, ce qui fait sortir le programme avec un code non nul.
+func runCompare(args []string) error {
+	flags := flag.NewFlagSet("compare", flag.ContinueOnError)
+	var opts benchmark.CompareOptions
+	flags.Float64Var(&opts.Threshold, "threshold", 0.1, "hausse relative de la médiane signalée comme régression")
+	flags.Float64Var(&opts.Alpha, "alpha", 0.05, "seuil de significativité du test de Mann-Whitney")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		return fmt.Errorf("Usage: compare [-threshold 0.1] [-alpha 0.05] before.json after.json")
+	}
+
+	before, err := benchmark.ReadFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	after, err := benchmark.ReadFile(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	comparisons := benchmark.Compare(before, after, opts)
+	if len(comparisons) == 0 {
+		return fmt.Errorf("No common (solver, family, size, seed) group between %s and %s", flags.Arg(0), flags.Arg(1))
+	}
+	return benchmark.WriteComparisons(os.Stdout, comparisons)
+}
+
+/* runReport écrit le rapport HTML autonome d'un fichier de résultats */
+func runReport(args []string) error {
+	flags := flag.NewFlagSet("report", flag.ContinueOnError)
+	output := flags.String("o", "report.html", "fichier HTML de sortie")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("Usage: report [-o report.html] results.json")
+	}
+
+	results, err := benchmark.ReadFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	err = benchmark.WriteReport(file, results)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Rapport écrit dans %s\n", *output)
+	return nil
+}
+
+/* runMultipleKnapsack remplit plusieurs sacs avec les objets d'un fichier */
+func runMultipleKnapsack(args []string) error {
+	flags := flag.NewFlagSet("mkp", flag.ContinueOnError)
+	capacities := flags.String("capacities", "30,50,80", "capacités des sacs séparées par des virgules")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("Usage: mkp [-capacities 30,50,80] data.json")
+	}
+
+	sizes, err := parseInts(*capacities)
+	if err != nil {
+		return err
+	}
+	data, err := tools.LoadDataFromFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := solverContext()
+	defer cancel()
+	fmt.Println(tools.SolveMultipleKnapsack(ctx, data, sizes))
+	return nil
+}
+
+/* runBinPacking range les objets d'un fichier dans le plus petit nombre de boîtes */
+func runBinPacking(args []string) error {
+	flags := flag.NewFlagSet("bins", flag.ContinueOnError)
+	capacity := flags.Int("capacity", 80, "capacité de chaque boîte")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("Usage: bins [-capacity 80] data.json")
+	}
+
+	data, err := tools.LoadDataFromFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := solverContext()
+	defer cancel()
+	fmt.Println(tools.SolveBinPacking(ctx, data, *capacity))
+	return nil
+}
+
+/* solverContext renvoie le contexte d'une résolution, limité à tools.SolverTimeout et avec affichage de la progression */
+func solverContext() (context.Context, context.CancelFunc) {
+	ctx := tools.WithProgress(context.Background())
+	if tools.SolverTimeout > 0 {
+		return context.WithTimeout(ctx, tools.SolverTimeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+/* splitList découpe une liste séparée par des virgules, nil si elle est vide */
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func parseInts(s string) ([]int, error) {
+	var values []int
+	for _, item := range splitList(s) {
+		v, err := strconv.Atoi(item)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid integer %q", item)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+func parseFloats(s string) ([]float64, error) {
+	var values []float64
+	for _, item := range splitList(s) {
+		v, err := strconv.ParseFloat(item, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid number %q", item)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
@@ -0,0 +1,104 @@
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+)
+
+/* CertificateKind désigne la forme d'une preuve d'optimalité */
+type CertificateKind int
+
+const (
+	DualBound  CertificateKind = iota // borne de la relaxation lagrangienne égale (à l'arrondi près) à la valeur trouvée
+	SearchTree                        // arbre de séparation dont chaque feuille est irréalisable ou bornée par la valeur trouvée
+	ValueTable                        // empreinte de la dernière ligne de la programmation dynamique
+)
+
+/* Leaf est une feuille de l'arbre de séparation : les Depth premiers objets de Certificate.Order sont fixés, à 1 pour les positions de Taken (croissantes) et à 0 pour les autres */
+type Leaf struct {
+	Depth int
+	Taken []int
+}
+
+// Certificate prouve qu'aucune solution ne dépasse la valeur trouvée. Pour DualBound, Num/Den est un
+// multiplicateur λ >= 0 : λ·C + somme(max(0, p_j - λ·w_j)) majore l'optimum, et la preuve est valide si la
+// partie entière de cette borne est la valeur trouvée. Pour SearchTree, Order contient les objets de valeur
+// positive par rapport valeur/poids décroissant et Leaves les feuilles de l'arbre exploré : elles doivent
+// recouvrir toutes les affectations de ces objets, et chacune être irréalisable ou de borne au plus égale à la
+// valeur trouvée. Pour ValueTable, Digest est l'empreinte (RowDigest) de la ligne f(c), c = 0..C, des meilleures
+// valeurs de chaque capacité : le vérificateur refait la programmation dynamique en O(n·C) et exige la même
+// ligne et f(C) égal à la valeur trouvée. Ce certificat ne dispense donc pas du calcul, il atteste que la table
+// du solveur est celle d'un calcul indépendant.
+//
+// Seul branch_and_bound certifie tous ses résultats optimaux (dual, sinon arbre) ; dp et dp_hirschberg les
+// certifient par la borne duale, sinon par la table ; minknap et exhaustive seulement quand la borne duale
+// suffit, faute de quoi leur résultat optimal n'a pas de certificat.
+type Certificate struct {
+	Kind     CertificateKind
+	Num, Den int
+	Order    []int
+	Leaves   []Leaf
+	Digest   []byte
+}
+
+/* TableCertificate renvoie le certificat de la dernière ligne row d'une programmation dynamique */
+func TableCertificate(row []int) *Certificate {
+	return &Certificate{Kind: ValueTable, Digest: RowDigest(row)}
+}
+
+/* RowDigest renvoie l'empreinte SHA-256 d'une ligne de valeurs, chacune codée sur 8 octets petit-boutistes */
+func RowDigest(row []int) []byte {
+	h := sha256.New()
+	buf := make([]byte, 8)
+	for _, v := range row {
+		binary.LittleEndian.PutUint64(buf, uint64(int64(v)))
+		h.Write(buf)
+	}
+	return h.Sum(nil)
+}
+
+// DualCertificate renvoie le certificat de la relaxation lagrangienne pour le multiplicateur optimal (le
+// rapport valeur/poids de l'objet critique), nil si sa borne dépasse value d'au moins 1 ou si l'instance contient
+// des poids négatifs. C'est la borne de Dantzig : elle suffit pour les instances peu corrélées.
+func DualCertificate(inst Instance, value int) *Certificate {
+	if inst.Capacity < 0 {
+		return nil
+	}
+	for _, obj := range inst.Objects {
+		if obj.Weight < 0 {
+			return nil
+		}
+	}
+
+	cert := &Certificate{Kind: DualBound, Num: 0, Den: 1}
+	remaining := inst.Capacity
+	for _, i := range RatioOrder(inst.Objects) {
+		obj := inst.Objects[i]
+		if obj.Value <= 0 {
+			break
+		}
+		if obj.Weight > remaining {
+			cert.Num, cert.Den = obj.Value, obj.Weight
+			break
+		}
+		remaining -= obj.Weight
+	}
+
+	// Den·borne = Num·C + somme(max(0, Den·p_j - Num·w_j)) doit être inférieur à Den·(value + 1) ; les produits
+	// dépassent un int sur les grandes instances, le calcul est donc fait en big.Int comme dans le vérificateur
+	num, den := big.NewInt(int64(cert.Num)), big.NewInt(int64(cert.Den))
+	scaled := new(big.Int).Mul(num, big.NewInt(int64(inst.Capacity)))
+	gain, cost := new(big.Int), new(big.Int)
+	for _, obj := range inst.Objects {
+		gain.Mul(den, big.NewInt(int64(obj.Value)))
+		gain.Sub(gain, cost.Mul(num, big.NewInt(int64(obj.Weight))))
+		if gain.Sign() > 0 {
+			scaled.Add(scaled, gain)
+		}
+	}
+	if scaled.Cmp(new(big.Int).Mul(den, big.NewInt(int64(value)+1))) >= 0 {
+		return nil
+	}
+	return cert
+}
@@ -1,6 +1,73 @@
 package common
 
+import "sort"
+
 type Objects struct {
-	Weight int `json:"weight"`
-	Value  int `json:"value"`
+	ID       int    `json:"id,omitempty"` // identifiant de l'objet dans le fichier d'origine
+	Name     string `json:"name,omitempty"`
+	Weight   int    `json:"weight"`
+	Value    int    `json:"value"`
+	Quantity int    `json:"quantity,omitempty"` // nombre d'exemplaires disponibles (sac borné), 0 équivaut à 1
+	Class    int    `json:"class,omitempty"`    // classe de l'objet (sac à choix multiples : un objet par classe)
+	Values   []int  `json:"values,omitempty"`   // valeurs selon plusieurs critères (sac multi-objectif)
+}
+
+/* Criteria renvoie les valeurs de l'objet selon chaque critère, Value seul s'il n'en a pas d'autres */
+func (o Objects) Criteria() []int {
+	if len(o.Values) > 0 {
+		return o.Values
+	}
+	return []int{o.Value}
+}
+
+/* Copies renvoie le nombre d'exemplaires disponibles de l'objet */
+func (o Objects) Copies() int {
+	if o.Quantity <= 0 {
+		return 1
+	}
+	return o.Quantity
+}
+
+/* BetterRatio indique si l'objet a a un rapport valeur/poids strictement meilleur que b (produit en croix, sans division par zéro) */
+func BetterRatio(a, b Objects) bool {
+	// Un objet de poids nul a un rapport infini du signe de sa valeur (nul si sa valeur est nulle)
+	if a.Weight == 0 && b.Weight == 0 {
+		return sign(a.Value) > sign(b.Value)
+	}
+	if a.Weight == 0 && a.Value == 0 {
+		a.Weight = 1
+	}
+	if b.Weight == 0 && b.Value == 0 {
+		b.Weight = 1
+	}
+	return a.Value*b.Weight > b.Value*a.Weight
+}
+
+func sign(x int) int {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	}
+	return 0
+}
+
+/* SortByRatio trie les objets par rapport valeur/poids décroissant */
+func SortByRatio(objects []Objects) {
+	sort.SliceStable(objects, func(i, j int) bool {
+		return BetterRatio(objects[i], objects[j])
+	})
+}
+
+/* RatioOrder renvoie les indices des objets triés par rapport valeur/poids décroissant, sans modifier la tranche */
+func RatioOrder(objects []Objects) []int {
+	order := make([]int, len(objects))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return BetterRatio(objects[order[i]], objects[order[j]])
+	})
+	return order
 }
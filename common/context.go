@@ -0,0 +1,52 @@
+package common
+
+import "context"
+
+/* pollInterval est le nombre d'appels à Poll.Done entre deux consultations du contexte */
+const pollInterval = 1024
+
+// Poll consulte l'annulation d'un contexte à intervalles réguliers, pour un coût négligeable dans les boucles
+// internes. Il porte aussi le Reporter du contexte (nil s'il n'y en a pas), dont il n'examine l'horloge qu'aux
+// mêmes intervalles.
+type Poll struct {
+	*Reporter
+	ctx     context.Context
+	calls   int
+	done    bool
+	checked bool
+}
+
+func NewPoll(ctx context.Context) *Poll {
+	return &Poll{Reporter: NewReporter(ctx), ctx: ctx}
+}
+
+/* Done indique si le contexte a été annulé ; le premier appel le consulte toujours, et une fois vrai Done le reste */
+func (p *Poll) Done() bool {
+	p.checked = !p.done && p.calls%pollInterval == 0
+	if p.checked {
+		p.done = p.ctx.Err() != nil
+	}
+	p.calls++
+	return p.done
+}
+
+/* Stopped indique si un appel à Done a déjà constaté l'annulation, sans consulter le contexte */
+func (p *Poll) Stopped() bool {
+	return p.done
+}
+
+/* Due indique si un événement de suivi est dû ; seuls les appels à Done qui ont consulté le contexte peuvent le rendre vrai */
+func (p *Poll) Due() bool {
+	return p.checked && p.Reporter.Due()
+}
+
+// Interrupt marque un résultat comme interrompu : la solution n'est plus garantie optimale et bound est la
+// meilleure borne supérieure connue de l'optimum (0 si elle est inconnue, jamais moins que la valeur trouvée).
+func (r *Result) Interrupt(bound int) {
+	r.Optimal = false
+	r.Stats.Interrupted = true
+	r.Bound = bound
+	if bound != 0 && bound < r.Value {
+		r.Bound = r.Value
+	}
+}
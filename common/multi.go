@@ -0,0 +1,42 @@
+package common
+
+/* MultiObjects est un objet qui consomme plusieurs ressources à la fois (poids, volume, budget...) */
+type MultiObjects struct {
+	Weights []int `json:"weights"`
+	Value   int   `json:"value"`
+}
+
+/* MultiInstance est une instance du sac à dos multidimensionnel : une capacité par ressource */
+type MultiInstance struct {
+	Objects    []MultiObjects
+	Capacities []int
+}
+
+/* ToMulti convertit une instance classique en instance multidimensionnelle à une seule ressource */
+func (inst Instance) ToMulti() MultiInstance {
+	objects := make([]MultiObjects, len(inst.Objects))
+	for i, obj := range inst.Objects {
+		objects[i] = MultiObjects{Weights: []int{obj.Weight}, Value: obj.Value}
+	}
+	return MultiInstance{Objects: objects, Capacities: []int{inst.Capacity}}
+}
+
+/* NewMultiResult construit un Result à partir des indices choisis, avec la consommation de chaque ressource */
+func NewMultiResult(inst MultiInstance, indices []int, optimal bool) Result {
+	objects := make([]Objects, len(inst.Objects))
+	for i, obj := range inst.Objects {
+		objects[i].Value = obj.Value
+		if len(obj.Weights) > 0 {
+			objects[i].Weight = obj.Weights[0]
+		}
+	}
+
+	res := NewResult(Instance{Objects: objects}, indices, optimal)
+	res.Weights = make([]int, len(inst.Capacities))
+	for _, i := range res.Indices {
+		for k, w := range inst.Objects[i].Weights {
+			res.Weights[k] += w
+		}
+	}
+	return res
+}
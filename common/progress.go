@@ -0,0 +1,104 @@
+package common
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+/* Progress est un événement de suivi émis périodiquement par un algorithme long ; les champs sans objet restent nuls */
+type Progress struct {
+	Solver    string        // nom de l'algorithme
+	Elapsed   time.Duration // temps écoulé depuis le début de la résolution
+	Nodes     int64         // noeuds explorés, états engendrés ou cases calculées
+	Best      int           // meilleure valeur trouvée jusque-là
+	Rows      int           // lignes de programmation dynamique calculées
+	TotalRows int           // nombre total de lignes de programmation dynamique
+	Iteration int           // itération de LLL
+	K         int           // indice du vecteur courant de LLL
+	Swaps     int           // nombre d'échanges effectués par LLL
+	Done      bool          // vrai pour le dernier événement, émis à la fin de la résolution (même interrompue)
+}
+
+/* ProgressFunc reçoit les événements de suivi ; elle est appelée depuis le solveur et doit rendre la main rapidement */
+type ProgressFunc func(Progress)
+
+type progressKey struct{}
+
+type progressConfig struct {
+	fn       ProgressFunc
+	interval time.Duration
+}
+
+// WithProgress renvoie un contexte qui demande aux solveurs d'appeler fn au plus une fois par interval (à chaque
+// point de contrôle si interval est nul), puis une dernière fois à la fin de la résolution avec Done à vrai.
+func WithProgress(ctx context.Context, interval time.Duration, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, progressConfig{fn: fn, interval: interval})
+}
+
+/* ProgressChannel transmet les événements sur un canal, en abandonnant ceux que le destinataire n'est pas prêt à recevoir (sauf le dernier) */
+func ProgressChannel(ch chan<- Progress) ProgressFunc {
+	return func(p Progress) {
+		if p.Done {
+			ch <- p
+			return
+		}
+		select {
+		case ch <- p:
+		default:
+		}
+	}
+}
+
+// Reporter émet les événements de suivi d'une résolution en respectant l'intervalle demandé. Un Reporter nil
+// (contexte sans suivi) ne fait rien, si bien que les solveurs l'appellent sans condition. Il peut être partagé
+// entre goroutines.
+type Reporter struct {
+	fn       ProgressFunc
+	interval time.Duration
+	start    time.Time
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+/* NewReporter renvoie le Reporter du contexte, nil si WithProgress n'a pas été utilisé */
+func NewReporter(ctx context.Context) *Reporter {
+	cfg, ok := ctx.Value(progressKey{}).(progressConfig)
+	if !ok || cfg.fn == nil {
+		return nil
+	}
+	now := time.Now()
+	return &Reporter{fn: cfg.fn, interval: cfg.interval, start: now, last: now}
+}
+
+/* Due indique si l'intervalle depuis le dernier événement est écoulé */
+func (r *Reporter) Due() bool {
+	if r == nil {
+		return false
+	}
+	if r.interval <= 0 {
+		return true
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return time.Since(r.last) >= r.interval
+}
+
+/* Report émet un événement, qu'il soit dû ou non */
+func (r *Reporter) Report(p Progress) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.last = time.Now()
+	p.Elapsed = r.last.Sub(r.start)
+	r.mu.Unlock()
+	r.fn(p)
+}
+
+/* Finish émet le dernier événement de la résolution */
+func (r *Reporter) Finish(p Progress) {
+	p.Done = true
+	r.Report(p)
+}
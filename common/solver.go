@@ -0,0 +1,156 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+/* Instance regroupe les objets et la capacité du sac à dos à résoudre */
+type Instance struct {
+	Objects  []Objects
+	Capacity int
+}
+
+/* Stats contient les statistiques d'exécution d'un solveur */
+type Stats struct {
+	Nodes       int64         // nombre de noeuds (ou de cases) explorés
+	Fixed       int           // nombre d'objets fixés à 0 ou à 1 par la réduction préalable
+	Duration    time.Duration // temps de résolution
+	Interrupted bool          // vrai si le contexte a été annulé (ou son délai dépassé) avant la fin de la résolution
+}
+
+/* Result est le résultat commun renvoyé par tous les solveurs */
+type Result struct {
+	Indices []int // indices des objets choisis dans Instance.Objects, triés
+	Counts  []int // nombre d'exemplaires choisis de chaque objet (sacs bornés et non bornés), nil pour le sac 0/1
+	Value   int
+	Weight  int
+	Weights []int // consommation de chaque ressource (sac multidimensionnel), nil sinon
+	Optimal bool  // vrai si le solveur garantit l'optimalité de la solution
+	Bound   int   // borne supérieure prouvée de la valeur optimale, 0 si inconnue
+	Stats   Stats
+
+	Certificate *Certificate // preuve d'optimalité vérifiable sans résoudre à nouveau, nil si le solveur n'en fournit pas
+}
+
+// Solver est l'interface commune à tous les algorithmes du sac à dos. Si le contexte est annulé ou son délai
+// dépassé, Solve s'arrête au plus vite et renvoie la meilleure solution trouvée jusque-là, avec Optimal à faux et
+// Stats.Interrupted à vrai, sans erreur.
+type Solver interface {
+	Name() string
+	Solve(ctx context.Context, inst Instance) (Result, error)
+}
+
+/* NewResult construit un Result à partir des indices choisis en recalculant la valeur et le poids */
+func NewResult(inst Instance, indices []int, optimal bool) Result {
+	sorted := make([]int, len(indices))
+	copy(sorted, indices)
+	sort.Ints(sorted)
+
+	res := Result{Indices: sorted, Optimal: optimal}
+	for _, i := range sorted {
+		res.Value += inst.Objects[i].Value
+		res.Weight += inst.Objects[i].Weight
+	}
+	if optimal {
+		res.Bound = res.Value
+	}
+	return res
+}
+
+/* NewCountResult construit un Result à partir du nombre d'exemplaires choisis de chaque objet */
+func NewCountResult(inst Instance, counts []int, optimal bool) Result {
+	res := Result{Indices: make([]int, 0), Counts: counts, Optimal: optimal}
+	for i, count := range counts {
+		if count > 0 {
+			res.Indices = append(res.Indices, i)
+			res.Value += count * inst.Objects[i].Value
+			res.Weight += count * inst.Objects[i].Weight
+		}
+	}
+	if optimal {
+		res.Bound = res.Value
+	}
+	return res
+}
+
+/* Selected renvoie les objets correspondant aux indices du résultat */
+func (r Result) Selected(inst Instance) []Objects {
+	objects := make([]Objects, len(r.Indices))
+	for k, i := range r.Indices {
+		objects[k] = inst.Objects[i]
+	}
+	return objects
+}
+
+var registry = map[string]Solver{}
+
+/* variants contient les solveurs de variantes (non borné, choix multiples) qui ne résolvent pas le sac 0/1 */
+var variants = map[string]Solver{}
+
+/* Register enregistre un solveur du sac 0/1 sous son nom, appelée depuis init() par chaque paquet d'algorithme */
+func Register(s Solver) {
+	register(registry, s)
+}
+
+// RegisterVariant enregistre un solveur d'une variante qui donne un autre sens à l'instance (exemplaires
+// illimités, un objet par classe) : il est accessible par Lookup mais pas listé par Solvers ni SolverNames, qui
+// ne renvoient que des solveurs comparables sur une même instance 0/1.
+func RegisterVariant(s Solver) {
+	register(variants, s)
+}
+
+func register(into map[string]Solver, s Solver) {
+	name := s.Name()
+	_, solver := registry[name]
+	_, variant := variants[name]
+	if solver || variant {
+		panic(fmt.Sprintf("Solver %q registered twice", name))
+	}
+	into[name] = s
+}
+
+/* Lookup renvoie le solveur (du sac 0/1 ou d'une variante) enregistré sous le nom donné */
+func Lookup(name string) (Solver, error) {
+	if s, ok := registry[name]; ok {
+		return s, nil
+	}
+	if s, ok := variants[name]; ok {
+		return s, nil
+	}
+	return nil, fmt.Errorf("Unknown solver %q", name)
+}
+
+/* SolverNames renvoie les noms de tous les solveurs du sac 0/1 enregistrés, triés */
+func SolverNames() []string {
+	return sortedNames(registry)
+}
+
+/* Solvers renvoie tous les solveurs du sac 0/1 enregistrés, triés par nom */
+func Solvers() []Solver {
+	return sortedSolvers(registry)
+}
+
+/* Variants renvoie les solveurs de variantes enregistrés par RegisterVariant, triés par nom */
+func Variants() []Solver {
+	return sortedSolvers(variants)
+}
+
+func sortedNames(from map[string]Solver) []string {
+	names := make([]string, 0, len(from))
+	for name := range from {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedSolvers(from map[string]Solver) []Solver {
+	solvers := make([]Solver, 0, len(from))
+	for _, name := range sortedNames(from) {
+		solvers = append(solvers, from[name])
+	}
+	return solvers
+}
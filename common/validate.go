@@ -0,0 +1,261 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+)
+
+/* Rule désigne une règle de validation d'une instance */
+type Rule int
+
+const (
+	NegativeCapacity  Rule = iota // capacité négative
+	NonPositiveWeight             // poids nul ou négatif
+	NonPositiveValue              // valeur nulle ou négative
+	NegativeQuantity              // nombre d'exemplaires négatif
+	TooHeavy                      // objet plus lourd que la capacité, il ne peut jamais être choisi
+	WeightOverflow                // la somme des poids dépasse la capacité d'un int
+	ValueOverflow                 // la somme des valeurs dépasse la capacité d'un int
+)
+
+var ruleNames = map[Rule]string{
+	NegativeCapacity:  "negative capacity",
+	NonPositiveWeight: "non-positive weight",
+	NonPositiveValue:  "non-positive value",
+	NegativeQuantity:  "negative quantity",
+	TooHeavy:          "heavier than capacity",
+	WeightOverflow:    "total weight overflows int",
+	ValueOverflow:     "total value overflows int",
+}
+
+func (r Rule) String() string {
+	if name, ok := ruleNames[r]; ok {
+		return name
+	}
+	return fmt.Sprintf("rule %d", int(r))
+}
+
+const maxInt = int(^uint(0) >> 1)
+
+/* ValidationError indique quel objet (Index, -1 pour l'instance elle-même) enfreint quelle règle */
+type ValidationError struct {
+	Index int
+	Rule  Rule
+}
+
+func (e *ValidationError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("Invalid instance: %s", e.Rule)
+	}
+	return fmt.Sprintf("Invalid object %d: %s", e.Index, e.Rule)
+}
+
+/* ValidationErrors regroupe toutes les infractions trouvées dans une instance */
+type ValidationErrors []*ValidationError
+
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, len(errs))
+	for k, err := range errs {
+		messages[k] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+/* Has indique si l'une des erreurs porte sur la règle donnée */
+func (errs ValidationErrors) Has(rule Rule) bool {
+	for _, err := range errs {
+		if err.Rule == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate vérifie qu'une instance respecte les hypothèses des solveurs exacts : capacité positive, poids et
+// valeurs strictement positifs, objets qui tiennent dans le sac et sommes des poids et des valeurs (exemplaires
+// compris) représentables sans débordement. Renvoie nil ou une ValidationErrors listant toutes les infractions.
+func Validate(inst Instance) error {
+	var errs ValidationErrors
+	if inst.Capacity < 0 {
+		errs = append(errs, &ValidationError{Index: -1, Rule: NegativeCapacity})
+	}
+	errs = append(errs, validateObjects(inst.Objects, inst.Capacity)...)
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// ValidateObjects applique les règles de Validate qui ne dépendent pas de la capacité (poids, valeurs,
+// exemplaires et débordements) : c'est la vérification possible au chargement d'un fichier sans capacité.
+func ValidateObjects(objects []Objects) error {
+	if errs := validateObjects(objects, -1); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+/* validateObjects vérifie chaque objet ; la règle TooHeavy n'est appliquée que si capacity est positive */
+func validateObjects(objects []Objects, capacity int) ValidationErrors {
+	var errs ValidationErrors
+	totalWeight, totalValue := 0, 0
+	weightOverflow, valueOverflow := false, false
+	for i, obj := range objects {
+		if obj.Weight <= 0 {
+			errs = append(errs, &ValidationError{Index: i, Rule: NonPositiveWeight})
+		}
+		if obj.Value <= 0 {
+			errs = append(errs, &ValidationError{Index: i, Rule: NonPositiveValue})
+		}
+		if obj.Quantity < 0 {
+			errs = append(errs, &ValidationError{Index: i, Rule: NegativeQuantity})
+		}
+		if obj.Weight > capacity && capacity >= 0 {
+			errs = append(errs, &ValidationError{Index: i, Rule: TooHeavy})
+		}
+
+		if !weightOverflow && obj.Weight > 0 && !addFits(&totalWeight, obj.Weight, obj.Copies()) {
+			weightOverflow = true
+			errs = append(errs, &ValidationError{Index: i, Rule: WeightOverflow})
+		}
+		if !valueOverflow && obj.Value > 0 && !addFits(&totalValue, obj.Value, obj.Copies()) {
+			valueOverflow = true
+			errs = append(errs, &ValidationError{Index: i, Rule: ValueOverflow})
+		}
+	}
+	return errs
+}
+
+/* Without renvoie les erreurs qui ne portent sur aucune des règles données, nil s'il n'en reste aucune */
+func (errs ValidationErrors) Without(rules ...Rule) ValidationErrors {
+	var kept ValidationErrors
+	for _, err := range errs {
+		ignored := false
+		for _, rule := range rules {
+			ignored = ignored || err.Rule == rule
+		}
+		if !ignored {
+			kept = append(kept, err)
+		}
+	}
+	return kept
+}
+
+/* addFits ajoute x·copies à *total si le résultat reste représentable, et indique si c'est le cas */
+func addFits(total *int, x, copies int) bool {
+	if x > (maxInt-*total)/copies {
+		return false
+	}
+	*total += x * copies
+	return true
+}
+
+/* NormalizeOptions choisit les corrections appliquées par Normalize */
+type NormalizeOptions struct {
+	DropTooHeavy  bool // retirer les objets plus lourds que la capacité
+	DropWorthless bool // retirer les objets de poids positif et de valeur nulle ou négative, jamais utiles
+	FixZeroWeight bool // retirer les objets de poids nul et de valeur positive, toujours choisis
+	DivideByGCD   bool // diviser les poids et la capacité par le PGCD des poids
+}
+
+/* DefaultNormalizeOptions applique toutes les corrections */
+var DefaultNormalizeOptions = NormalizeOptions{DropTooHeavy: true, DropWorthless: true, FixZeroWeight: true, DivideByGCD: true}
+
+/* Normalized est une instance normalisée accompagnée de quoi revenir à l'instance d'origine */
+type Normalized struct {
+	Instance Instance
+	Original Instance
+	Mapping  []int // Mapping[k] : indice dans l'instance d'origine de l'objet k de l'instance normalisée
+	Fixed    []int // indices d'origine des objets de poids nul toujours choisis
+	Divisor  int   // PGCD par lequel les poids ont été divisés (1 sinon)
+}
+
+// Normalize simplifie une instance sans changer sa valeur optimale : les objets trop lourds et ceux qui ont un
+// poids positif sans valeur positive sont retirés, les objets de poids nul et de valeur positive sont mis de côté (ils font partie de toute solution optimale) et les
+// poids sont divisés par leur PGCD, la capacité étant arrondie à l'inférieur. Les autres infractions (poids ou
+// valeurs négatifs, débordements) ne sont pas corrigées, Validate peut être appelée sur le résultat.
+func Normalize(inst Instance, opts NormalizeOptions) Normalized {
+	n := Normalized{Original: inst, Divisor: 1, Fixed: make([]int, 0)}
+	n.Instance.Capacity = inst.Capacity
+
+	for i, obj := range inst.Objects {
+		if opts.DropTooHeavy && obj.Weight > inst.Capacity {
+			continue
+		}
+		if opts.DropWorthless && obj.Weight > 0 && obj.Value <= 0 {
+			continue
+		}
+		if opts.FixZeroWeight && obj.Weight == 0 && obj.Value > 0 {
+			n.Fixed = append(n.Fixed, i)
+			continue
+		}
+		n.Instance.Objects = append(n.Instance.Objects, obj)
+		n.Mapping = append(n.Mapping, i)
+	}
+
+	if opts.DivideByGCD {
+		g := 0
+		for _, obj := range n.Instance.Objects {
+			g = gcd(g, obj.Weight)
+		}
+		if g > 1 {
+			n.Divisor = g
+			for k := range n.Instance.Objects {
+				n.Instance.Objects[k].Weight /= g
+			}
+			n.Instance.Capacity = floorDiv(inst.Capacity, g)
+		}
+	}
+	return n
+}
+
+// Restore traduit un résultat obtenu sur l'instance normalisée en résultat sur l'instance d'origine : les
+// indices sont remis dans la numérotation d'origine, les objets mis de côté sont ajoutés, et la valeur, le
+// poids et la borne sont recalculés.
+func (n Normalized) Restore(res Result) Result {
+	var restored Result
+	if res.Counts != nil {
+		counts := make([]int, len(n.Original.Objects))
+		for k, count := range res.Counts {
+			counts[n.Mapping[k]] = count
+		}
+		for _, i := range n.Fixed {
+			counts[i] = n.Original.Objects[i].Copies()
+		}
+		restored = NewCountResult(n.Original, counts, res.Optimal)
+	} else {
+		indices := append([]int(nil), n.Fixed...)
+		for _, k := range res.Indices {
+			indices = append(indices, n.Mapping[k])
+		}
+		restored = NewResult(n.Original, indices, res.Optimal)
+	}
+
+	if !res.Optimal && res.Bound > 0 {
+		restored.Bound = res.Bound + restored.Value - res.Value
+	}
+	restored.Stats = res.Stats
+	return restored
+}
+
+func gcd(a, b int) int {
+	if a < 0 {
+		a = -a
+	}
+	if b < 0 {
+		b = -b
+	}
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && a < 0 {
+		q--
+	}
+	return q
+}
@@ -0,0 +1,106 @@
+package common_test
+
+import (
+	"reflect"
+	"testing"
+
+	"../common"
+)
+
+func TestValidate(t *testing.T) {
+	maxInt := int(^uint(0) >> 1)
+	tests := []struct {
+		name    string
+		inst    common.Instance
+		index   int
+		rule    common.Rule
+		invalid bool
+	}{
+		{"valid", common.Instance{Objects: []common.Objects{{Weight: 2, Value: 3}}, Capacity: 5}, 0, 0, false},
+		{"negative capacity", common.Instance{Objects: []common.Objects{}, Capacity: -1}, -1, common.NegativeCapacity, true},
+		{"zero weight", common.Instance{Objects: []common.Objects{{Weight: 0, Value: 3}}, Capacity: 5}, 0, common.NonPositiveWeight, true},
+		{"negative weight", common.Instance{Objects: []common.Objects{{Weight: 1, Value: 1}, {Weight: -2, Value: 3}}, Capacity: 5}, 1, common.NonPositiveWeight, true},
+		{"zero value", common.Instance{Objects: []common.Objects{{Weight: 2, Value: 0}}, Capacity: 5}, 0, common.NonPositiveValue, true},
+		{"negative quantity", common.Instance{Objects: []common.Objects{{Weight: 2, Value: 3, Quantity: -1}}, Capacity: 5}, 0, common.NegativeQuantity, true},
+		{"too heavy", common.Instance{Objects: []common.Objects{{Weight: 6, Value: 3}}, Capacity: 5}, 0, common.TooHeavy, true},
+		{"weight overflow", common.Instance{Objects: []common.Objects{{Weight: maxInt, Value: 1}, {Weight: maxInt, Value: 1}}, Capacity: maxInt}, 1, common.WeightOverflow, true},
+		{"value overflow", common.Instance{Objects: []common.Objects{{Weight: 1, Value: maxInt / 2, Quantity: 3}}, Capacity: 5}, 0, common.ValueOverflow, true},
+	}
+
+	for _, test := range tests {
+		err := common.Validate(test.inst)
+		if !test.invalid {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", test.name, err)
+			}
+			continue
+		}
+
+		errs, ok := err.(common.ValidationErrors)
+		if !ok || !errs.Has(test.rule) {
+			t.Errorf("%s: expected rule %q, got %v", test.name, test.rule, err)
+			continue
+		}
+		for _, e := range errs {
+			if e.Rule == test.rule && e.Index != test.index {
+				t.Errorf("%s: expected index %d, got %d", test.name, test.index, e.Index)
+			}
+		}
+	}
+}
+
+func TestValidateObjectsIgnoresCapacity(t *testing.T) {
+	if err := common.ValidateObjects([]common.Objects{{Weight: 1000, Value: 1}}); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	err := common.ValidateObjects([]common.Objects{{Weight: 0, Value: 1}, {Weight: 1, Value: -1}})
+	if errs, ok := err.(common.ValidationErrors); !ok || !errs.Has(common.NonPositiveWeight) || !errs.Has(common.NonPositiveValue) {
+		t.Errorf("Expected weight and value errors, got %v", err)
+	}
+	if errs := err.(common.ValidationErrors).Without(common.NonPositiveWeight, common.NonPositiveValue); errs != nil {
+		t.Errorf("Without kept %v", errs)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	objects := []common.Objects{
+		{Weight: 4, Value: 5},  // 0 : gardé
+		{Weight: 12, Value: 9}, // 1 : trop lourd
+		{Weight: 0, Value: 7},  // 2 : poids nul, toujours choisi
+		{Weight: 6, Value: 0},  // 3 : sans valeur
+		{Weight: 8, Value: 3},  // 4 : gardé
+	}
+	inst := common.Instance{Objects: objects, Capacity: 11}
+
+	tests := []struct {
+		name     string
+		opts     common.NormalizeOptions
+		mapping  []int
+		fixed    []int
+		divisor  int
+		capacity int
+	}{
+		{"none", common.NormalizeOptions{}, []int{0, 1, 2, 3, 4}, []int{}, 1, 11},
+		{"drop too heavy", common.NormalizeOptions{DropTooHeavy: true}, []int{0, 2, 3, 4}, []int{}, 1, 11},
+		{"drop worthless", common.NormalizeOptions{DropWorthless: true}, []int{0, 1, 2, 4}, []int{}, 1, 11},
+		{"fix zero weight", common.NormalizeOptions{FixZeroWeight: true}, []int{0, 1, 3, 4}, []int{2}, 1, 11},
+		{"divide by gcd", common.NormalizeOptions{DropTooHeavy: true, DropWorthless: true, FixZeroWeight: true, DivideByGCD: true}, []int{0, 4}, []int{2}, 4, 2},
+	}
+
+	for _, test := range tests {
+		n := common.Normalize(inst, test.opts)
+		if !reflect.DeepEqual(n.Mapping, test.mapping) || !reflect.DeepEqual(n.Fixed, test.fixed) {
+			t.Errorf("%s: mapping %v fixed %v, expected %v and %v", test.name, n.Mapping, n.Fixed, test.mapping, test.fixed)
+		}
+		if n.Divisor != test.divisor || n.Instance.Capacity != test.capacity {
+			t.Errorf("%s: divisor %d capacity %d, expected %d and %d", test.name, n.Divisor, n.Instance.Capacity, test.divisor, test.capacity)
+		}
+	}
+
+	// Restore remet la solution dans la numérotation d'origine, avec les objets mis de côté
+	n := common.Normalize(inst, common.DefaultNormalizeOptions)
+	restored := n.Restore(common.NewResult(n.Instance, []int{0}, true))
+	if restored.Value != 12 || restored.Weight != 4 || !reflect.DeepEqual(restored.Indices, []int{0, 2}) {
+		t.Errorf("Unexpected restored result %+v", restored)
+	}
+}
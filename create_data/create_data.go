@@ -6,12 +6,11 @@ import (
 	"io/ioutil"
 	"math/rand"
 	"time"
+
+	"../common"
 )
 
-type Objects struct {
-	Weight int `json:"weight"`
-	Value  int `json:"value"`
-}
+type Objects = common.Objects
 
 func GenerateData(numExamples int) {
 	// Génère les exemples aléatoires
@@ -40,8 +39,9 @@ func GenerateRandomExamples(numExamples int) []Objects {
 
 	var examples []Objects
 	for i := 0; i < numExamples; i++ {
-		weight := random.Intn(100)
-		value := random.Intn(100)
+		// Un poids nul rendrait le rapport valeur/poids infini, et une valeur nulle est refusée au chargement
+		weight := random.Intn(100) + 1
+		value := random.Intn(100) + 1
 
 		example := Objects{
 			Weight: weight,
@@ -0,0 +1,78 @@
+package create_data_test
+
+import (
+	"reflect"
+	"testing"
+
+	"../common"
+	"../create_data"
+)
+
+func TestGenerateIsReproducible(t *testing.T) {
+	for _, family := range create_data.Families() {
+		inst, cfg, err := create_data.Generate(create_data.Config{Family: family, N: 40})
+		if err != nil {
+			t.Fatalf("%s: %v", family, err)
+		}
+		if cfg.Seed == 0 || cfg.Range != 1000 || cfg.CapacityRatio != 0.5 {
+			t.Errorf("%s: defaults not applied: %+v", family, cfg)
+		}
+		again, _, err := create_data.Generate(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(inst, again) {
+			t.Errorf("%s: the returned configuration does not give back the same instance", family)
+		}
+	}
+}
+
+func TestFamilies(t *testing.T) {
+	for _, family := range create_data.Families() {
+		inst, _, err := create_data.Generate(create_data.Config{Family: family, N: 200, Range: 100, Seed: 7})
+		if err != nil {
+			t.Fatalf("%s: %v", family, err)
+		}
+		if len(inst.Objects) != 200 {
+			t.Errorf("%s: %d objects, expected 200", family, len(inst.Objects))
+		}
+		if err := common.ValidateObjects(inst.Objects); err != nil {
+			t.Errorf("%s: %v", family, err)
+		}
+
+		total := 0
+		for _, obj := range inst.Objects {
+			total += obj.Weight
+			if obj.Weight < 1 || obj.Value < 1 {
+				t.Fatalf("%s: object %+v has a non-positive weight or value", family, obj)
+			}
+			switch family {
+			case create_data.StronglyCorrelated:
+				if obj.Value != obj.Weight+10 {
+					t.Fatalf("%s: object %+v, expected value weight+10", family, obj)
+				}
+			case create_data.SubsetSum:
+				if obj.Value != obj.Weight {
+					t.Fatalf("%s: object %+v, expected value equal to weight", family, obj)
+				}
+			}
+		}
+		if inst.Capacity != total/2 {
+			t.Errorf("%s: capacity %d, expected half the total weight %d", family, inst.Capacity, total)
+		}
+	}
+}
+
+func TestInvalidConfigs(t *testing.T) {
+	configs := map[string]create_data.Config{
+		"negative size":     {N: -1},
+		"ratio too large":   {N: 5, CapacityRatio: 1},
+		"unknown family":    {Family: "unknown", N: 5},
+		"recursive spanner": {Family: create_data.Spanner, SpannerBase: create_data.Spanner, N: 5},
+	}
+	for name, cfg := range configs {
+		if _, _, err := create_data.Generate(cfg); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
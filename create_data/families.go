@@ -0,0 +1,178 @@
+package create_data
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"../common"
+)
+
+/* Family désigne une famille d'instances de test de Pisinger */
+type Family string
+
+const (
+	Uncorrelated              Family = "uncorrelated"
+	WeaklyCorrelated          Family = "weakly_correlated"
+	StronglyCorrelated        Family = "strongly_correlated"
+	InverseStronglyCorrelated Family = "inverse_strongly_correlated"
+	AlmostStronglyCorrelated  Family = "almost_strongly_correlated"
+	SubsetSum                 Family = "subset_sum"
+	Spanner                   Family = "spanner"
+	ProfitCeiling             Family = "profit_ceiling"
+	Circle                    Family = "circle"
+)
+
+/* Families renvoie toutes les familles d'instances disponibles */
+func Families() []Family {
+	return []Family{
+		Uncorrelated, WeaklyCorrelated, StronglyCorrelated, InverseStronglyCorrelated,
+		AlmostStronglyCorrelated, SubsetSum, Spanner, ProfitCeiling, Circle,
+	}
+}
+
+/* Config décrit une instance à générer ; les champs nuls prennent leur valeur par défaut */
+type Config struct {
+	Family        Family  `json:"family"`
+	N             int     `json:"n"`
+	Range         int     `json:"range"`          // R : les poids sont tirés dans [1, R], 1000 par défaut
+	Seed          int64   `json:"seed"`           // graine du générateur, 0 : tirée de l'heure
+	CapacityRatio float64 `json:"capacity_ratio"` // capacité = ratio * somme des poids, 0.5 par défaut
+
+	SpannerBase       Family `json:"spanner_base,omitempty"`       // famille des objets de base, strongly_correlated par défaut
+	SpannerSize       int    `json:"spanner_size,omitempty"`       // nombre v d'objets de base, 2 par défaut
+	SpannerMultiplier int    `json:"spanner_multiplier,omitempty"` // multiplicateur maximal m, 10 par défaut
+}
+
+/* withDefaults renvoie la configuration avec les valeurs par défaut et une graine fixée */
+func (cfg Config) withDefaults() Config {
+	if cfg.Family == "" {
+		cfg.Family = Uncorrelated
+	}
+	if cfg.Range <= 0 {
+		cfg.Range = 1000
+	}
+	if cfg.Seed == 0 {
+		cfg.Seed = time.Now().UnixNano()
+	}
+	if cfg.CapacityRatio <= 0 {
+		cfg.CapacityRatio = 0.5
+	}
+	if cfg.Family == Spanner {
+		if cfg.SpannerBase == "" {
+			cfg.SpannerBase = StronglyCorrelated
+		}
+		if cfg.SpannerSize <= 0 {
+			cfg.SpannerSize = 2
+		}
+		if cfg.SpannerMultiplier <= 0 {
+			cfg.SpannerMultiplier = 10
+		}
+	}
+	return cfg
+}
+
+// Generate génère une instance de la famille demandée. La configuration renvoyée contient les valeurs par
+// défaut appliquées et la graine effectivement utilisée : la repasser à Generate redonne la même instance.
+func Generate(cfg Config) (common.Instance, Config, error) {
+	cfg = cfg.withDefaults()
+	if cfg.N < 0 {
+		return common.Instance{}, cfg, fmt.Errorf("Invalid number of objects %d", cfg.N)
+	}
+	if cfg.CapacityRatio >= 1 {
+		return common.Instance{}, cfg, fmt.Errorf("Capacity ratio must be in ]0, 1[, got %g", cfg.CapacityRatio)
+	}
+
+	random := rand.New(rand.NewSource(cfg.Seed))
+	var objects []Objects
+
+	if cfg.Family == Spanner {
+		if cfg.SpannerBase == Spanner {
+			return common.Instance{}, cfg, fmt.Errorf("Spanner instances cannot be based on spanner instances")
+		}
+
+		// Objets de base, réduits par le multiplicateur, puis chaque objet est un multiple d'un objet de base
+		base := make([]Objects, cfg.SpannerSize)
+		m := cfg.SpannerMultiplier
+		for k := range base {
+			obj, err := generateObject(random, cfg.SpannerBase, cfg.Range)
+			if err != nil {
+				return common.Instance{}, cfg, err
+			}
+			base[k] = Objects{Weight: ceilDiv(2*obj.Weight, m), Value: ceilDiv(2*obj.Value, m)}
+		}
+		for i := 0; i < cfg.N; i++ {
+			obj := base[random.Intn(len(base))]
+			a := randRange(random, 1, m)
+			objects = append(objects, Objects{Weight: a * obj.Weight, Value: a * obj.Value})
+		}
+	} else {
+		for i := 0; i < cfg.N; i++ {
+			obj, err := generateObject(random, cfg.Family, cfg.Range)
+			if err != nil {
+				return common.Instance{}, cfg, err
+			}
+			objects = append(objects, obj)
+		}
+	}
+
+	total := 0
+	for _, obj := range objects {
+		total += obj.Weight
+	}
+	capacity := int(cfg.CapacityRatio * float64(total))
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	return common.Instance{Objects: objects, Capacity: capacity}, cfg, nil
+}
+
+/* generateObject tire un objet de la famille donnée, de poids dans [1, r] */
+func generateObject(random *rand.Rand, family Family, r int) (Objects, error) {
+	w := randRange(random, 1, r)
+	var p int
+
+	switch family {
+	case Uncorrelated:
+		p = randRange(random, 1, r)
+	case WeaklyCorrelated:
+		p = randRange(random, w-r/10, w+r/10)
+		if p < 1 {
+			p = 1
+		}
+	case StronglyCorrelated:
+		p = w + r/10
+	case InverseStronglyCorrelated:
+		p = randRange(random, 1, r)
+		w = p + r/10
+	case AlmostStronglyCorrelated:
+		p = randRange(random, w+r/10-r/500, w+r/10+r/500)
+	case SubsetSum:
+		p = w
+	case ProfitCeiling:
+		// p = d * ceil(w / d) avec d = 3
+		p = 3 * ceilDiv(w, 3)
+	case Circle:
+		// p = d * sqrt(4R² - (w - 2R)²) avec d = 2/3
+		x := float64(w - 2*r)
+		p = int(2.0 / 3.0 * math.Sqrt(4*float64(r)*float64(r)-x*x))
+	default:
+		return Objects{}, fmt.Errorf("Unknown instance family %q", family)
+	}
+
+	return Objects{Weight: w, Value: p}, nil
+}
+
+/* randRange tire un entier uniforme dans [lo, hi] */
+func randRange(random *rand.Rand, lo, hi int) int {
+	if hi <= lo {
+		return lo
+	}
+	return lo + random.Intn(hi-lo+1)
+}
+
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
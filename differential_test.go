@@ -0,0 +1,417 @@
+package main_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"./common"
+	"./create_data"
+	"./fptas"
+	"./multi_objective"
+	"./multiple_choice"
+	"./multiple_knapsack"
+	"./reduction"
+	"./reserch_exhastive"
+	"./subset_sum"
+	_ "./tools"
+	"./verification"
+)
+
+// Classement des solveurs du sac 0/1 : un solveur exact doit trouver l'optimum, une approximation garantit au
+// moins ratio fois l'optimum (0 : seulement une solution réalisable). Les variantes (exemplaires illimités, un
+// objet par classe) sont enregistrées à part et n'apparaissent pas dans common.Solvers.
+var (
+	exactSolvers = map[string]bool{
+		"dp": true, "dp_hirschberg": true, "exhaustive": true, "exhaustive_parallel": true,
+		"branch_and_bound": true, "branch_and_bound_mt": true, "minknap": true,
+		"meet_in_the_middle": true, "multidimensional": true, "bounded": true,
+	}
+	approximationRatios = map[string]float64{
+		"greedy": 0, "greedy_skip": 0, "greedy_half": 0.5, "fptas": 1 - fptas.DefaultEpsilon,
+	}
+)
+
+/* differentialInstances génère de petites instances de toutes les familles, résolubles par la recherche exhaustive */
+func differentialInstances(t *testing.T) []common.Instance {
+	seeds := 25
+	if testing.Short() {
+		seeds = 5
+	}
+
+	var instances []common.Instance
+	for _, family := range create_data.Families() {
+		for seed := 1; seed <= seeds; seed++ {
+			cfg := create_data.Config{Family: family, N: 1 + seed%14, Range: 100, Seed: int64(seed)}
+			inst, _, err := create_data.Generate(cfg)
+			if err != nil {
+				t.Fatalf("Failed to generate %s instance (seed %d): %v", family, seed, err)
+			}
+			instances = append(instances, inst)
+		}
+	}
+
+	// Cas limites : instance vide, capacité nulle, aucun objet ne rentre
+	instances = append(instances,
+		common.Instance{Objects: []common.Objects{}, Capacity: 10},
+		common.Instance{Objects: []common.Objects{{Weight: 3, Value: 4}, {Weight: 1, Value: 1}}, Capacity: 0},
+		common.Instance{Objects: []common.Objects{{Weight: 30, Value: 4}, {Weight: 11, Value: 1}}, Capacity: 10},
+	)
+	return instances
+}
+
+func TestSolversAreClassified(t *testing.T) {
+	for _, s := range common.Solvers() {
+		_, approximation := approximationRatios[s.Name()]
+		if !exactSolvers[s.Name()] && !approximation {
+			t.Errorf("Solver %s is not classified for the differential tests", s.Name())
+		}
+	}
+}
+
+func TestVariantsAreSeparate(t *testing.T) {
+	for _, name := range []string{"unbounded", "multiple_choice"} {
+		if _, err := common.Lookup(name); err != nil {
+			t.Errorf("Variant %s should be found by Lookup: %v", name, err)
+		}
+		for _, s := range common.Solvers() {
+			if s.Name() == name {
+				t.Errorf("Variant %s is listed among the 0/1 solvers", name)
+			}
+		}
+	}
+}
+
+func TestDifferential(t *testing.T) {
+	var solvers []common.Solver
+	for _, s := range common.Solvers() {
+		solvers = append(solvers, s)
+		if exactSolvers[s.Name()] {
+			solvers = append(solvers, reduction.Wrap(s))
+		}
+	}
+
+	for k, inst := range differentialInstances(t) {
+		optimum, _, _ := reserch_exhastive.KnapsackIndices(inst.Objects, inst.Capacity)
+
+		for _, s := range solvers {
+			name := fmt.Sprintf("%s/instance %d", s.Name(), k)
+			res, err := s.Solve(context.Background(), inst)
+			if err != nil {
+				t.Errorf("%s: %v", name, err)
+				continue
+			}
+			if err := verification.Verify(inst, res); err != nil {
+				t.Errorf("%s: %v", name, err)
+				continue
+			}
+
+			if res.Value > optimum {
+				t.Errorf("%s: value %d exceeds the optimum %d", name, res.Value, optimum)
+			}
+			if res.Bound != 0 && res.Bound < optimum {
+				t.Errorf("%s: bound %d is below the optimum %d", name, res.Bound, optimum)
+			}
+			if res.Optimal && res.Value != optimum {
+				t.Errorf("%s: claims optimality with value %d, optimum %d", name, res.Value, optimum)
+			}
+
+			inner := s.Name()
+			if r, ok := s.(reduction.Solver); ok {
+				inner = r.Inner.Name()
+			}
+			if exactSolvers[inner] && (!res.Optimal || res.Value != optimum) {
+				t.Errorf("%s: exact solver found %d (optimal: %t), optimum %d", name, res.Value, res.Optimal, optimum)
+			}
+			if ratio := approximationRatios[inner]; float64(res.Value) < ratio*float64(optimum) {
+				t.Errorf("%s: value %d is below %g times the optimum %d", name, res.Value, ratio, optimum)
+			}
+		}
+	}
+}
+
+// Les variantes ont chacune leur oracle par énumération, sur des instances dérivées de differentialInstances
+// assez petites pour que l'énumération reste rapide.
+
+/* smallInstances renvoie les instances différentielles d'au plus n objets */
+func smallInstances(t *testing.T, n int) []common.Instance {
+	var instances []common.Instance
+	for _, inst := range differentialInstances(t) {
+		if len(inst.Objects) <= n {
+			instances = append(instances, inst)
+		}
+	}
+	return instances
+}
+
+/* bruteForceUnbounded énumère tous les nombres d'exemplaires qui tiennent dans le sac */
+func bruteForceUnbounded(objects []common.Objects, capacity int) int {
+	var walk func(i, capacity int) int
+	walk = func(i, capacity int) int {
+		if i == len(objects) {
+			return 0
+		}
+		best := 0
+		for count := 0; count*objects[i].Weight <= capacity; count++ {
+			if v := count*objects[i].Value + walk(i+1, capacity-count*objects[i].Weight); v > best {
+				best = v
+			}
+			if objects[i].Weight == 0 {
+				break
+			}
+		}
+		return best
+	}
+	return walk(0, capacity)
+}
+
+func TestDifferentialUnbounded(t *testing.T) {
+	solver, err := common.Lookup("unbounded")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, inst := range smallInstances(t, 8) {
+		// La capacité est limitée pour borner le nombre de combinaisons d'exemplaires
+		if inst.Capacity > 60 {
+			inst.Capacity = 60
+		}
+		res, err := solver.Solve(context.Background(), inst)
+		if err != nil {
+			t.Errorf("Instance %d: %v", k, err)
+			continue
+		}
+		if err := verification.Check(inst, res); err != nil {
+			t.Errorf("Instance %d: %v", k, err)
+		}
+		if optimum := bruteForceUnbounded(inst.Objects, inst.Capacity); !res.Optimal || res.Value != optimum {
+			t.Errorf("Instance %d: value %d (optimal: %t), optimum %d", k, res.Value, res.Optimal, optimum)
+		}
+	}
+}
+
+/* bruteForceMultipleChoice énumère les choix d'un objet par classe, -1 si aucun ne tient dans le sac */
+func bruteForceMultipleChoice(objects []common.Objects, capacity, classes int) int {
+	best := -1
+	var walk func(class, weight, value int)
+	walk = func(class, weight, value int) {
+		if weight > capacity {
+			return
+		}
+		if class == classes {
+			if value > best {
+				best = value
+			}
+			return
+		}
+		for _, obj := range objects {
+			if obj.Class == class {
+				walk(class+1, weight+obj.Weight, value+obj.Value)
+			}
+		}
+	}
+	walk(0, 0, 0)
+	return best
+}
+
+func TestDifferentialMultipleChoice(t *testing.T) {
+	solver, err := common.Lookup("multiple_choice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, inst := range smallInstances(t, 12) {
+		if len(inst.Objects) == 0 {
+			continue
+		}
+		// Les objets sont répartis en classes de 1 à 4 objets
+		classes := (len(inst.Objects) + 2) / 3
+		objects := append([]common.Objects(nil), inst.Objects...)
+		for i := range objects {
+			objects[i].Class = i % classes
+		}
+		inst := common.Instance{Objects: objects, Capacity: inst.Capacity}
+
+		optimum := bruteForceMultipleChoice(objects, inst.Capacity, classes)
+		res, err := solver.Solve(context.Background(), inst)
+		if optimum < 0 {
+			if !errors.Is(err, multiple_choice.ErrInfeasible) {
+				t.Errorf("Instance %d: expected ErrInfeasible, got %v", k, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Instance %d: %v", k, err)
+			continue
+		}
+		if err := verification.Check(inst, res); err != nil {
+			t.Errorf("Instance %d: %v", k, err)
+		}
+		if len(res.Indices) != classes || !res.Optimal || res.Value != optimum {
+			t.Errorf("Instance %d: %d objects for %d classes, value %d (optimal: %t), optimum %d", k, len(res.Indices), classes, res.Value, res.Optimal, optimum)
+		}
+	}
+}
+
+/* bruteForceMultipleKnapsack essaie toutes les affectations de chaque objet à un sac ou à aucun */
+func bruteForceMultipleKnapsack(objects []common.Objects, capacities []int) int {
+	loads := make([]int, len(capacities))
+	best := 0
+	var walk func(i, value int)
+	walk = func(i, value int) {
+		if i == len(objects) {
+			if value > best {
+				best = value
+			}
+			return
+		}
+		walk(i+1, value)
+		for j := range loads {
+			if loads[j]+objects[i].Weight <= capacities[j] {
+				loads[j] += objects[i].Weight
+				walk(i+1, value+objects[i].Value)
+				loads[j] -= objects[i].Weight
+			}
+		}
+	}
+	walk(0, 0)
+	return best
+}
+
+func TestDifferentialMultipleKnapsack(t *testing.T) {
+	for k, inst := range smallInstances(t, 10) {
+		// La capacité est partagée entre deux sacs inégaux
+		capacities := []int{inst.Capacity / 3, inst.Capacity - inst.Capacity/3}
+		res, err := multiple_knapsack.Knapsack(context.Background(), inst.Objects, capacities, multiple_knapsack.Options{})
+		if err != nil {
+			t.Errorf("Instance %d: %v", k, err)
+			continue
+		}
+		for j, bag := range res.Bags {
+			if err := verification.Check(common.Instance{Objects: inst.Objects, Capacity: capacities[j]}, common.NewResult(inst, bag, false)); err != nil {
+				t.Errorf("Instance %d, bag %d: %v", k, j, err)
+			}
+		}
+		if optimum := bruteForceMultipleKnapsack(inst.Objects, capacities); !res.Optimal || res.Value != optimum {
+			t.Errorf("Instance %d: value %d (optimal: %t), optimum %d", k, res.Value, res.Optimal, optimum)
+		}
+	}
+}
+
+/* bruteForceFront renvoie les points (poids, valeur1, valeur2) non dominés de tous les sous-ensembles réalisables */
+func bruteForceFront(objects []common.Objects, capacity int) map[[3]int]bool {
+	var points [][3]int
+	for mask := 0; mask < 1<<len(objects); mask++ {
+		p := [3]int{}
+		for i, obj := range objects {
+			if mask&(1<<i) != 0 {
+				p[0] += obj.Weight
+				p[1] += obj.Values[0]
+				p[2] += obj.Values[1]
+			}
+		}
+		if p[0] <= capacity {
+			points = append(points, p)
+		}
+	}
+
+	front := make(map[[3]int]bool)
+	for _, p := range points {
+		dominated := false
+		for _, q := range points {
+			if q != p && q[0] <= p[0] && q[1] >= p[1] && q[2] >= p[2] {
+				dominated = true
+				break
+			}
+		}
+		if !dominated {
+			front[p] = true
+		}
+	}
+	return front
+}
+
+func TestDifferentialMultiObjective(t *testing.T) {
+	for k, inst := range smallInstances(t, 10) {
+		if len(inst.Objects) == 0 {
+			continue
+		}
+		// Second critère : la légèreté de l'objet, en conflit avec la valeur dans les familles corrélées
+		objects := append([]common.Objects(nil), inst.Objects...)
+		for i := range objects {
+			objects[i].Values = []int{objects[i].Value, 101 - objects[i].Weight}
+		}
+
+		front, truncated, err := multi_objective.ParetoFront(context.Background(), objects, inst.Capacity, multi_objective.Options{})
+		if err != nil || truncated {
+			t.Errorf("Instance %d: truncated %t, error %v", k, truncated, err)
+			continue
+		}
+		got := make(map[[3]int]bool, len(front))
+		for _, s := range front {
+			got[[3]int{s.Weight, s.Values[0], s.Values[1]}] = true
+			res := common.NewResult(common.Instance{Objects: objects}, s.Indices, false)
+			if res.Weight != s.Weight || res.Weight > inst.Capacity {
+				t.Errorf("Instance %d: solution %+v weighs %d, capacity %d", k, s, res.Weight, inst.Capacity)
+			}
+		}
+		if expected := bruteForceFront(objects, inst.Capacity); len(got) != len(front) || !reflect.DeepEqual(got, expected) {
+			t.Errorf("Instance %d: front of %d solutions, expected %d", k, len(front), len(expected))
+		}
+	}
+}
+
+func TestDifferentialSubsetSum(t *testing.T) {
+	for k, inst := range smallInstances(t, 14) {
+		// Cible : la capacité de l'instance, atteignable ou non
+		weights := make([]*big.Int, len(inst.Objects))
+		for i, obj := range inst.Objects {
+			weights[i] = big.NewInt(int64(obj.Weight))
+		}
+		target := big.NewInt(int64(inst.Capacity))
+
+		reachable := false
+		for mask := 0; mask < 1<<len(weights) && !reachable; mask++ {
+			sum := 0
+			for i, obj := range inst.Objects {
+				if mask&(1<<i) != 0 {
+					sum += obj.Weight
+				}
+			}
+			reachable = sum == inst.Capacity
+		}
+
+		// Schroeppel-Shamir est exact ; Howgrave-Graham-Joux, probabiliste, peut échouer mais jamais se tromper
+		bits, err := subset_sum.SchroeppelShamir(context.Background(), weights, target)
+		checkSubsetSum(t, fmt.Sprintf("Schroeppel-Shamir/instance %d", k), weights, target, bits, err, reachable, true)
+		bits, err = subset_sum.HowgraveGrahamJoux(context.Background(), weights, target, subset_sum.Options{Seed: int64(k + 1)})
+		checkSubsetSum(t, fmt.Sprintf("Howgrave-Graham-Joux/instance %d", k), weights, target, bits, err, reachable, false)
+	}
+}
+
+func checkSubsetSum(t *testing.T, name string, weights []*big.Int, target *big.Int, bits []byte, err error, reachable, exact bool) {
+	t.Helper()
+	if errors.Is(err, subset_sum.ErrNoSolution) {
+		if reachable && exact {
+			t.Errorf("%s: no solution found although the target is reachable", name)
+		}
+		return
+	}
+	if err != nil {
+		t.Errorf("%s: %v", name, err)
+		return
+	}
+	if !reachable {
+		t.Errorf("%s: found %v for an unreachable target", name, bits)
+	}
+	sum := new(big.Int)
+	for i, b := range bits {
+		if b == 1 {
+			sum.Add(sum, weights[i])
+		}
+	}
+	if len(bits) != len(weights) || sum.Cmp(target) != 0 {
+		t.Errorf("%s: bits %v sum to %s, target %s", name, bits, sum, target)
+	}
+}
@@ -0,0 +1,174 @@
+package formats
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"../common"
+	"../create_data"
+)
+
+/* Format désigne un format de fichier d'instance */
+type Format string
+
+const (
+	JSON      Format = "json"  // schéma JSON étendu (ou tableau d'objets nu, l'ancien format de data.json)
+	Pisinger  Format = "kp"    // format texte des instances knapPI de Pisinger
+	ORLibrary Format = "orlib" // format du sac à dos multidimensionnel de l'OR-Library (mknap)
+	CSV       Format = "csv"
+)
+
+var ErrUnknownFormat = errors.New("Unable to detect the instance file format")
+
+/* ErrMultidimensional est renvoyée à l'écriture d'une instance à plusieurs ressources dans un format qui n'en a qu'une */
+var ErrMultidimensional = errors.New("Multidimensional instances can only be written in the OR-Library format")
+
+/* Document est une instance accompagnée de ses métadonnées */
+type Document struct {
+	Name      string
+	Instance  common.Instance
+	Multi     *common.MultiInstance // instance multidimensionnelle (OR-Library), nil sinon
+	Optimum   *int                  // valeur optimale connue, nil si inconnue
+	Generator *create_data.Config   // paramètres du générateur ayant produit l'instance, nil si inconnus
+}
+
+/* Read lit un fichier d'instance, le format étant déterminé par l'extension ou, à défaut, par le contenu */
+func Read(filename string) (Document, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return Document{}, err
+	}
+
+	format, err := DetectFormat(filename, content)
+	if err != nil {
+		return Document{}, err
+	}
+
+	doc, err := ReadFormat(bytes.NewReader(content), format)
+	if err != nil {
+		return Document{}, fmt.Errorf("Failed to read %s as %s: %v", filename, format, err)
+	}
+	return doc, nil
+}
+
+/* ReadFormat lit une instance dans le format donné */
+func ReadFormat(r io.Reader, format Format) (Document, error) {
+	switch format {
+	case JSON:
+		return readJSON(r)
+	case Pisinger:
+		return readPisinger(r)
+	case ORLibrary:
+		return readORLibrary(r)
+	case CSV:
+		return readCSV(r)
+	}
+	return Document{}, fmt.Errorf("Unknown format %q", format)
+}
+
+/* Write écrit un fichier d'instance dans le format correspondant à son extension (JSON par défaut) */
+func Write(filename string, doc Document) error {
+	format, ok := formatFromExtension(filename)
+	if !ok {
+		format = JSON
+	}
+	if err := checkDimensions(doc, format); err != nil {
+		return err
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	if err := WriteFormat(file, doc, format); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// WriteFormat écrit une instance dans le format donné. Seul l'OR-Library représente une instance à plusieurs
+// ressources : les autres formats ne lisent que doc.Instance et renvoient ErrMultidimensional plutôt que
+// d'écrire une instance vide.
+func WriteFormat(w io.Writer, doc Document, format Format) error {
+	if err := checkDimensions(doc, format); err != nil {
+		return err
+	}
+	switch format {
+	case JSON:
+		return writeJSON(w, doc)
+	case Pisinger:
+		return writePisinger(w, doc)
+	case ORLibrary:
+		return writeORLibrary(w, doc)
+	case CSV:
+		return writeCSV(w, doc)
+	}
+	return fmt.Errorf("Unknown format %q", format)
+}
+
+/* checkDimensions vérifie que le format peut représenter toutes les ressources du document */
+func checkDimensions(doc Document, format Format) error {
+	if doc.Multi != nil && len(doc.Multi.Capacities) > 1 && format != ORLibrary {
+		return fmt.Errorf("%w: %d resources, cannot write %s", ErrMultidimensional, len(doc.Multi.Capacities), format)
+	}
+	return nil
+}
+
+func formatFromExtension(filename string) (Format, bool) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		return JSON, true
+	case ".kp":
+		return Pisinger, true
+	case ".csv":
+		return CSV, true
+	case ".orlib", ".mknap":
+		return ORLibrary, true
+	}
+	return "", false
+}
+
+// DetectFormat détermine le format d'un fichier : d'abord par son extension, puis par son contenu (JSON s'il
+// commence par [ ou {, knapPI s'il contient les lignes "n" et "c", CSV si sa première ligne contient une
+// virgule ou un commentaire #, OR-Library s'il ne contient que des nombres).
+func DetectFormat(filename string, content []byte) (Format, error) {
+	if format, ok := formatFromExtension(filename); ok {
+		return format, nil
+	}
+
+	text := strings.TrimSpace(string(content))
+	if strings.HasPrefix(text, "[") || strings.HasPrefix(text, "{") {
+		return JSON, nil
+	}
+
+	lines := strings.Split(text, "\n")
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "n" {
+			return Pisinger, nil
+		}
+	}
+
+	first := strings.TrimSpace(lines[0])
+	if strings.HasPrefix(first, "#") || strings.Contains(first, ",") {
+		return CSV, nil
+	}
+
+	for _, field := range strings.Fields(text) {
+		if strings.Trim(field, "0123456789.-") != "" {
+			return "", ErrUnknownFormat
+		}
+	}
+	if text == "" {
+		return "", ErrUnknownFormat
+	}
+	return ORLibrary, nil
+}
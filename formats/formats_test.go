@@ -0,0 +1,163 @@
+package formats_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"../common"
+	"../formats"
+)
+
+func document() formats.Document {
+	optimum := 12
+	return formats.Document{
+		Name: "exemple",
+		Instance: common.Instance{Objects: []common.Objects{
+			{ID: 1, Weight: 4, Value: 5},
+			{ID: 2, Weight: 3, Value: 4},
+			{ID: 3, Weight: 6, Value: 7},
+		}, Capacity: 10},
+		Optimum: &optimum,
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, format := range []formats.Format{formats.JSON, formats.Pisinger, formats.CSV, formats.ORLibrary} {
+		doc := document()
+		var buf bytes.Buffer
+		if err := formats.WriteFormat(&buf, doc, format); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		read, err := formats.ReadFormat(&buf, format)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+
+		// L'OR-Library ne garde ni le nom ni les identifiants
+		if format == formats.ORLibrary {
+			for i := range doc.Instance.Objects {
+				doc.Instance.Objects[i].ID = 0
+			}
+			doc.Name = ""
+			if read.Multi == nil || len(read.Multi.Capacities) != 1 {
+				t.Errorf("%s: expected a one-dimensional instance, got %+v", format, read.Multi)
+			}
+			read.Multi = nil
+		}
+		if !reflect.DeepEqual(read, doc) {
+			t.Errorf("%s: read %+v, expected %+v", format, read, doc)
+		}
+	}
+}
+
+func TestMultidimensionalRoundTrip(t *testing.T) {
+	optimum := 9
+	doc := formats.Document{
+		Multi: &common.MultiInstance{Objects: []common.MultiObjects{
+			{Weights: []int{2, 5}, Value: 4},
+			{Weights: []int{3, 1}, Value: 5},
+			{Weights: []int{4, 4}, Value: 6},
+		}, Capacities: []int{6, 6}},
+		Optimum: &optimum,
+	}
+
+	var buf bytes.Buffer
+	if err := formats.WriteFormat(&buf, doc, formats.ORLibrary); err != nil {
+		t.Fatal(err)
+	}
+	read, err := formats.ReadFormat(&buf, formats.ORLibrary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read, doc) {
+		t.Errorf("Read %+v, expected %+v", read, doc)
+	}
+
+	// Les autres formats n'ont qu'une ressource : l'instance lue ne peut pas y être écrite
+	dir := t.TempDir()
+	for _, format := range []formats.Format{formats.JSON, formats.Pisinger, formats.CSV} {
+		buf.Reset()
+		if err := formats.WriteFormat(&buf, read, format); !errors.Is(err, formats.ErrMultidimensional) || buf.Len() > 0 {
+			t.Errorf("%s: expected ErrMultidimensional and no output, got %v and %q", format, err, buf.String())
+		}
+		filename := filepath.Join(dir, "mknap."+string(format))
+		if err := formats.Write(filename, read); !errors.Is(err, formats.ErrMultidimensional) {
+			t.Errorf("%s: expected ErrMultidimensional, got %v", filename, err)
+		}
+		if _, err := os.Stat(filename); !os.IsNotExist(err) {
+			t.Errorf("%s: file created despite the error", filename)
+		}
+	}
+}
+
+func TestReadDetectsFormat(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"bare":     `[{"weight": 4, "value": 5}]`,
+		"pisinger": "knapPI_1_1_1000_1\nn 1\nc 10\nz 5\ntime 0.00\n1,5,4,1\n-----\n",
+		"csv":      "# capacity: 10\nweight,value\n4,5\n",
+		"orlib":    "1\n1 1 5\n5\n4\n10\n",
+	}
+	for name, content := range files {
+		filename := filepath.Join(dir, name)
+		if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		doc, err := formats.Read(filename)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if len(doc.Instance.Objects) != 1 || doc.Instance.Objects[0].Weight != 4 || doc.Instance.Objects[0].Value != 5 {
+			t.Errorf("%s: unexpected objects %+v", name, doc.Instance.Objects)
+		}
+		if name != "bare" && doc.Instance.Capacity != 10 {
+			t.Errorf("%s: capacity %d, expected 10", name, doc.Instance.Capacity)
+		}
+	}
+
+	if _, err := formats.DetectFormat("instance", []byte("poids valeur")); err != formats.ErrUnknownFormat {
+		t.Errorf("Expected ErrUnknownFormat, got %v", err)
+	}
+	if format, _ := formats.DetectFormat("instance.kp", []byte("[]")); format != formats.Pisinger {
+		t.Errorf("The extension must take precedence over the content, got %s", format)
+	}
+}
+
+func TestWriteUsesExtension(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "instance.csv")
+	if err := formats.Write(filename, document()); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(content), "# name: exemple\n# capacity: 10\n") {
+		t.Errorf("Expected a CSV file, got %q", content)
+	}
+}
+
+func TestInvalidFiles(t *testing.T) {
+	tests := []struct {
+		format  formats.Format
+		content string
+	}{
+		{formats.Pisinger, "n 3\nc 10\n1,5,4,0\n"},
+		{formats.Pisinger, "n 1\nc 10\n1,x,4,0\n"},
+		{formats.CSV, "weight\n4\n"},
+		{formats.CSV, "weight,value\n4,cinq\n"},
+		{formats.ORLibrary, "1\n2 1 0\n5\n"},
+		{formats.JSON, `{"capacity": "dix"}`},
+	}
+	for _, test := range tests {
+		if _, err := formats.ReadFormat(strings.NewReader(test.content), test.format); err == nil {
+			t.Errorf("%s: expected an error for %q", test.format, test.content)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package formats
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"../common"
+	"../create_data"
+)
+
+/* jsonDocument est le schéma JSON étendu des instances */
+type jsonDocument struct {
+	Name      string              `json:"name,omitempty"`
+	Capacity  int                 `json:"capacity"`
+	Optimum   *int                `json:"optimum,omitempty"`
+	Generator *create_data.Config `json:"generator,omitempty"`
+	Items     []common.Objects    `json:"items"`
+}
+
+/* readJSON lit le schéma étendu, ou un tableau d'objets nu dont la capacité est alors inconnue (0) */
+func readJSON(r io.Reader) (Document, error) {
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return Document{}, err
+	}
+
+	if strings.HasPrefix(strings.TrimSpace(string(content)), "[") {
+		var objects []common.Objects
+		if err := json.Unmarshal(content, &objects); err != nil {
+			return Document{}, err
+		}
+		return Document{Instance: common.Instance{Objects: objects}}, nil
+	}
+
+	var doc jsonDocument
+	if err := json.Unmarshal(content, &doc); err != nil {
+		return Document{}, err
+	}
+	return Document{
+		Name:      doc.Name,
+		Instance:  common.Instance{Objects: doc.Items, Capacity: doc.Capacity},
+		Optimum:   doc.Optimum,
+		Generator: doc.Generator,
+	}, nil
+}
+
+func writeJSON(w io.Writer, doc Document) error {
+	data, err := json.MarshalIndent(jsonDocument{
+		Name:      doc.Name,
+		Capacity:  doc.Instance.Capacity,
+		Optimum:   doc.Optimum,
+		Generator: doc.Generator,
+		Items:     doc.Instance.Objects,
+	}, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
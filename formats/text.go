@@ -0,0 +1,351 @@
+package formats
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"../common"
+)
+
+// readPisinger lit le premier problème d'un fichier knapPI de Pisinger :
+//
+//	knapPI_1_50_1000_1
+//	n 50
+//	c 995
+//	z 8373
+//	time 0.00
+//	1,94,485,0
+//	...
+//	-----
+//
+// chaque ligne d'objet contenant l'identifiant, la valeur, le poids et la variable de la solution optimale.
+func readPisinger(r io.Reader) (Document, error) {
+	var doc Document
+	n := -1
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "-----") {
+			break
+		}
+
+		if strings.Contains(line, ",") {
+			fields := strings.Split(line, ",")
+			if len(fields) < 3 {
+				return Document{}, fmt.Errorf("Invalid item line %q", line)
+			}
+			numbers, err := atoiAll(fields[:3])
+			if err != nil {
+				return Document{}, err
+			}
+			doc.Instance.Objects = append(doc.Instance.Objects, common.Objects{ID: numbers[0], Value: numbers[1], Weight: numbers[2]})
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			doc.Name = line
+			continue
+		}
+		switch fields[0] {
+		case "n", "c", "z":
+			value, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return Document{}, fmt.Errorf("Invalid line %q: %v", line, err)
+			}
+			switch fields[0] {
+			case "n":
+				n = value
+			case "c":
+				doc.Instance.Capacity = value
+			case "z":
+				doc.Optimum = &value
+			}
+		case "time":
+		default:
+			doc.Name = line
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Document{}, err
+	}
+
+	if n >= 0 && n != len(doc.Instance.Objects) {
+		return Document{}, fmt.Errorf("Expected %d items, got %d", n, len(doc.Instance.Objects))
+	}
+	return doc, nil
+}
+
+func writePisinger(w io.Writer, doc Document) error {
+	name := doc.Name
+	if name == "" {
+		name = "knapsack"
+	}
+
+	b := bufio.NewWriter(w)
+	fmt.Fprintln(b, name)
+	fmt.Fprintf(b, "n %d\n", len(doc.Instance.Objects))
+	fmt.Fprintf(b, "c %d\n", doc.Instance.Capacity)
+	if doc.Optimum != nil {
+		fmt.Fprintf(b, "z %d\n", *doc.Optimum)
+	}
+	fmt.Fprintln(b, "time 0.00")
+	for i, obj := range doc.Instance.Objects {
+		fmt.Fprintf(b, "%d,%d,%d,0\n", objectID(obj, i), obj.Value, obj.Weight)
+	}
+	fmt.Fprintln(b, "-----")
+	return b.Flush()
+}
+
+// readORLibrary lit le premier problème d'un fichier mknap de l'OR-Library : le nombre de problèmes, puis
+// "n m optimum", les n valeurs, les m lignes de n poids et les m capacités, séparés par des blancs.
+func readORLibrary(r io.Reader) (Document, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanWords)
+
+	next := func() (int, error) {
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return 0, err
+			}
+			return 0, io.ErrUnexpectedEOF
+		}
+		// L'optimum est parfois écrit avec une partie décimale nulle
+		return strconv.Atoi(strings.TrimSuffix(scanner.Text(), ".0"))
+	}
+
+	numbers := make([]int, 4)
+	for k := range numbers {
+		value, err := next()
+		if err != nil {
+			return Document{}, err
+		}
+		numbers[k] = value
+	}
+	n, m, optimum := numbers[1], numbers[2], numbers[3]
+	if n < 0 || m < 1 {
+		return Document{}, fmt.Errorf("Invalid problem size n=%d m=%d", n, m)
+	}
+
+	multi := common.MultiInstance{Objects: make([]common.MultiObjects, n), Capacities: make([]int, m)}
+	for i := range multi.Objects {
+		value, err := next()
+		if err != nil {
+			return Document{}, err
+		}
+		multi.Objects[i] = common.MultiObjects{Value: value, Weights: make([]int, m)}
+	}
+	for d := 0; d < m; d++ {
+		for i := 0; i < n; i++ {
+			weight, err := next()
+			if err != nil {
+				return Document{}, err
+			}
+			multi.Objects[i].Weights[d] = weight
+		}
+	}
+	for d := range multi.Capacities {
+		capacity, err := next()
+		if err != nil {
+			return Document{}, err
+		}
+		multi.Capacities[d] = capacity
+	}
+
+	doc := Document{Multi: &multi}
+	if optimum > 0 {
+		doc.Optimum = &optimum
+	}
+	if m == 1 {
+		doc.Instance.Capacity = multi.Capacities[0]
+		for _, obj := range multi.Objects {
+			doc.Instance.Objects = append(doc.Instance.Objects, common.Objects{Weight: obj.Weights[0], Value: obj.Value})
+		}
+	}
+	return doc, nil
+}
+
+/* writeORLibrary écrit un fichier mknap à un seul problème (optimum 0 s'il est inconnu) */
+func writeORLibrary(w io.Writer, doc Document) error {
+	multi := doc.Multi
+	if multi == nil {
+		single := doc.Instance.ToMulti()
+		multi = &single
+	}
+	optimum := 0
+	if doc.Optimum != nil {
+		optimum = *doc.Optimum
+	}
+
+	b := bufio.NewWriter(w)
+	fmt.Fprintln(b, 1)
+	fmt.Fprintf(b, "%d %d %d\n", len(multi.Objects), len(multi.Capacities), optimum)
+	for _, obj := range multi.Objects {
+		fmt.Fprintf(b, "%d ", obj.Value)
+	}
+	fmt.Fprintln(b)
+	for d := range multi.Capacities {
+		for _, obj := range multi.Objects {
+			fmt.Fprintf(b, "%d ", obj.Weights[d])
+		}
+		fmt.Fprintln(b)
+	}
+	for _, c := range multi.Capacities {
+		fmt.Fprintf(b, "%d ", c)
+	}
+	fmt.Fprintln(b)
+	return b.Flush()
+}
+
+// readCSV lit un fichier CSV avec une ligne d'en-tête (colonnes weight et value obligatoires ; id, name,
+// quantity et class facultatives). Les métadonnées sont données par des commentaires en tête de fichier :
+// "# capacity: 995", "# optimum: 8373", "# name: ...".
+func readCSV(r io.Reader) (Document, error) {
+	var doc Document
+	var body strings.Builder
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "#") {
+			body.WriteString(line)
+			body.WriteString("\n")
+			continue
+		}
+
+		parts := strings.SplitN(strings.TrimSpace(strings.TrimPrefix(trimmed, "#")), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		switch key {
+		case "name":
+			doc.Name = value
+		case "capacity", "optimum":
+			number, err := strconv.Atoi(value)
+			if err != nil {
+				return Document{}, fmt.Errorf("Invalid %s %q", key, value)
+			}
+			if key == "capacity" {
+				doc.Instance.Capacity = number
+			} else {
+				doc.Optimum = &number
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Document{}, err
+	}
+
+	records, err := csv.NewReader(strings.NewReader(body.String())).ReadAll()
+	if err != nil {
+		return Document{}, err
+	}
+	if len(records) == 0 {
+		return doc, nil
+	}
+
+	columns := make(map[string]int)
+	for k, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = k
+	}
+	for _, required := range []string{"weight", "value"} {
+		if _, ok := columns[required]; !ok {
+			return Document{}, fmt.Errorf("Missing %q column in CSV header", required)
+		}
+	}
+
+	for _, record := range records[1:] {
+		var obj common.Objects
+		for name, k := range columns {
+			if k >= len(record) {
+				continue
+			}
+			field := strings.TrimSpace(record[k])
+			if name == "name" {
+				obj.Name = field
+				continue
+			}
+
+			var target *int
+			switch name {
+			case "id":
+				target = &obj.ID
+			case "weight":
+				target = &obj.Weight
+			case "value":
+				target = &obj.Value
+			case "quantity":
+				target = &obj.Quantity
+			case "class":
+				target = &obj.Class
+			default:
+				continue
+			}
+			if field == "" {
+				continue
+			}
+			number, err := strconv.Atoi(field)
+			if err != nil {
+				return Document{}, fmt.Errorf("Invalid %s %q", name, field)
+			}
+			*target = number
+		}
+		doc.Instance.Objects = append(doc.Instance.Objects, obj)
+	}
+	return doc, nil
+}
+
+func writeCSV(w io.Writer, doc Document) error {
+	b := bufio.NewWriter(w)
+	if doc.Name != "" {
+		fmt.Fprintf(b, "# name: %s\n", doc.Name)
+	}
+	fmt.Fprintf(b, "# capacity: %d\n", doc.Instance.Capacity)
+	if doc.Optimum != nil {
+		fmt.Fprintf(b, "# optimum: %d\n", *doc.Optimum)
+	}
+
+	writer := csv.NewWriter(b)
+	writer.Write([]string{"id", "name", "weight", "value", "quantity", "class"})
+	for i, obj := range doc.Instance.Objects {
+		writer.Write([]string{
+			strconv.Itoa(objectID(obj, i)), obj.Name, strconv.Itoa(obj.Weight), strconv.Itoa(obj.Value),
+			strconv.Itoa(obj.Quantity), strconv.Itoa(obj.Class),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+	return b.Flush()
+}
+
+/* objectID renvoie l'identifiant d'un objet, ou sa position (à partir de 1) s'il n'en a pas */
+func objectID(obj common.Objects, i int) int {
+	if obj.ID != 0 {
+		return obj.ID
+	}
+	return i + 1
+}
+
+func atoiAll(fields []string) ([]int, error) {
+	numbers := make([]int, len(fields))
+	for k, field := range fields {
+		number, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid number %q", field)
+		}
+		numbers[k] = number
+	}
+	return numbers, nil
+}
@@ -0,0 +1,157 @@
+package fptas
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"../algorithme_glouton"
+	"../common"
+)
+
+/* DefaultEpsilon est la précision du solveur enregistré sous le nom "fptas" */
+const DefaultEpsilon = 0.1
+
+const infinity = int(^uint(0) >> 1)
+
+// Knapsack résout le problème du sac à dos à (1-epsilon) près : les valeurs sont divisées par
+// K = floor(epsilon*vmax/n), puis une programmation dynamique indexée par la valeur calcule le poids minimal
+// de chaque valeur réduite atteignable. Retourne la valeur obtenue, les indices des objets choisis, une borne
+// supérieure prouvée de la valeur optimale et le nombre de cases calculées. Si K vaut 1, la solution est exacte.
+// Si le contexte est annulé, la table n'est calculée que pour les premiers objets : la solution reste réalisable,
+// la borne est inconnue (0) et le dernier résultat avant l'erreur est vrai.
+func Knapsack(ctx context.Context, objects []common.Objects, capacity int, epsilon float64) (int, []int, int, int64, bool, error) {
+	if epsilon <= 0 || epsilon >= 1 {
+		return 0, nil, 0, 0, false, fmt.Errorf("Epsilon must be in ]0, 1[, got %g", epsilon)
+	}
+	if capacity < 0 {
+		return 0, nil, 0, 0, false, fmt.Errorf("Invalid capacity %d", capacity)
+	}
+
+	// Seuls les objets qui rentrent seuls dans le sac et de valeur positive peuvent être utiles
+	candidates := make([]int, 0, len(objects))
+	maxValue := 0
+	for i, obj := range objects {
+		if obj.Weight <= capacity && obj.Value > 0 {
+			candidates = append(candidates, i)
+			if obj.Value > maxValue {
+				maxValue = obj.Value
+			}
+		}
+	}
+	n := len(candidates)
+	if n == 0 {
+		return 0, []int{}, 0, 0, false, nil
+	}
+
+	// Facteur d'échelle entier : un K plus petit que epsilon*vmax/n ne fait que renforcer la garantie
+	scale := int(epsilon * float64(maxValue) / float64(n))
+	if scale < 1 {
+		scale = 1
+	}
+
+	scaled := make([]int, n)
+	totalScaled := 0
+	for k, i := range candidates {
+		scaled[k] = objects[i].Value / scale
+		totalScaled += scaled[k]
+	}
+
+	// minWeight[p] : poids minimal pour atteindre la valeur réduite p ; take[k] mémorise les choix de l'objet k
+	minWeight := make([]int, totalScaled+1)
+	for p := 1; p <= totalScaled; p++ {
+		minWeight[p] = infinity
+	}
+	take := make([][]uint64, n)
+
+	progress := common.NewReporter(ctx)
+	reachable, processed := 0, 0
+	for k, i := range candidates {
+		if ctx.Err() != nil {
+			break
+		}
+		if progress.Due() {
+			progress.Report(common.Progress{Solver: "fptas", Nodes: int64(k) * int64(totalScaled+1), Rows: k, TotalRows: n})
+		}
+		processed = k + 1
+		take[k] = make([]uint64, (totalScaled+64)/64)
+		w, p := objects[i].Weight, scaled[k]
+		reachable += p
+
+		for q := reachable; q >= p; q-- {
+			if minWeight[q-p] != infinity && minWeight[q-p]+w < minWeight[q] {
+				minWeight[q] = minWeight[q-p] + w
+				take[k][q/64] |= 1 << uint(q%64)
+			}
+		}
+	}
+
+	best := 0
+	for p := totalScaled; p >= 0; p-- {
+		if minWeight[p] <= capacity {
+			best = p
+			break
+		}
+	}
+
+	// Récupérer les indices des objets sélectionnés
+	indices := make([]int, 0)
+	value := 0
+	q := best
+	for k := processed - 1; k >= 0 && q > 0; k-- {
+		if take[k][q/64]&(1<<uint(q%64)) != 0 {
+			indices = append(indices, candidates[k])
+			value += objects[candidates[k]].Value
+			q -= scaled[k]
+		}
+	}
+
+	// Chaque objet perd moins de K lors de la réduction : OPT < K * (best + n) ; sans réduction, la solution est exacte
+	bound := scale * (best + n)
+	if scale == 1 {
+		bound = value
+	}
+	interrupted := processed < n
+	if interrupted {
+		bound = 0
+	}
+
+	cells := int64(processed) * int64(totalScaled+1)
+	progress.Finish(common.Progress{Solver: "fptas", Nodes: cells, Best: value, Rows: processed, TotalRows: n})
+	return value, indices, bound, cells, interrupted, nil
+}
+
+/* Solver adapte le schéma d'approximation à l'interface common.Solver */
+type Solver struct {
+	Epsilon float64
+}
+
+func init() {
+	common.Register(Solver{Epsilon: DefaultEpsilon})
+}
+
+func (s Solver) Name() string {
+	if s.Epsilon == DefaultEpsilon {
+		return "fptas"
+	}
+	return fmt.Sprintf("fptas_%g", s.Epsilon)
+}
+
+func (s Solver) Solve(ctx context.Context, inst common.Instance) (common.Result, error) {
+	startTime := time.Now()
+	_, indices, bound, cells, interrupted, err := Knapsack(ctx, inst.Objects, inst.Capacity, s.Epsilon)
+	if err != nil {
+		return common.Result{}, err
+	}
+
+	res := common.NewResult(inst, indices, false)
+	res.Bound = bound
+	res.Optimal = res.Value == bound
+	if interrupted {
+		res.Interrupt(algorithme_glouton.FractionalKnapsack(inst.Objects, inst.Capacity).Bound)
+		algorithme_glouton.Improve(&res, inst)
+	}
+	res.Stats.Nodes = cells
+	res.Stats.Duration = time.Since(startTime)
+	return res, nil
+}
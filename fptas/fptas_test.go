@@ -0,0 +1,73 @@
+package fptas_test
+
+import (
+	"context"
+	"testing"
+
+	"../algo_prog_dynamique"
+	"../create_data"
+	"../fptas"
+	"../verification"
+)
+
+func TestApproximationGuarantee(t *testing.T) {
+	for _, family := range create_data.Families() {
+		for seed := int64(1); seed <= 3; seed++ {
+			inst, _, err := create_data.Generate(create_data.Config{Family: family, N: 40, Range: 1000, Seed: seed})
+			if err != nil {
+				t.Fatal(err)
+			}
+			optimum := algo_prog_dynamique.KnapsackValue(inst.Objects, inst.Capacity)
+			for _, epsilon := range []float64{0.5, 0.1, 0.01} {
+				res, err := fptas.Solver{Epsilon: epsilon}.Solve(context.Background(), inst)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := verification.Check(inst, res); err != nil {
+					t.Errorf("%s seed %d, epsilon %g: %v", family, seed, epsilon, err)
+				}
+				if float64(res.Value) < (1-epsilon)*float64(optimum) || res.Bound < optimum {
+					t.Errorf("%s seed %d, epsilon %g: value %d, bound %d, optimum %d", family, seed, epsilon, res.Value, res.Bound, optimum)
+				}
+				if res.Optimal && res.Value != optimum {
+					t.Errorf("%s seed %d, epsilon %g: claims optimality with value %d, optimum %d", family, seed, epsilon, res.Value, optimum)
+				}
+			}
+		}
+	}
+}
+
+func TestInterrupted(t *testing.T) {
+	inst, _, err := create_data.Generate(create_data.Config{N: 100, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	res, err := fptas.Solver{Epsilon: fptas.DefaultEpsilon}.Solve(ctx, inst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := verification.Check(inst, res); err != nil {
+		t.Error(err)
+	}
+	optimum := algo_prog_dynamique.KnapsackValue(inst.Objects, inst.Capacity)
+	if !res.Stats.Interrupted || res.Optimal || res.Bound < optimum {
+		t.Errorf("Interrupted run: bound %d, optimum %d, %+v", res.Bound, optimum, res.Stats)
+	}
+}
+
+func TestInvalidParameters(t *testing.T) {
+	inst, _, err := create_data.Generate(create_data.Config{N: 5, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, epsilon := range []float64{0, 1, -0.5} {
+		if _, _, _, _, _, err := fptas.Knapsack(context.Background(), inst.Objects, inst.Capacity, epsilon); err == nil {
+			t.Errorf("Epsilon %g: expected an error", epsilon)
+		}
+	}
+	if _, _, _, _, _, err := fptas.Knapsack(context.Background(), inst.Objects, -1, 0.1); err == nil {
+		t.Error("Expected an error for a negative capacity")
+	}
+}
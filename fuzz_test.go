@@ -0,0 +1,84 @@
+package main_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"./formats"
+	"./merkel_hellman"
+	"./tools"
+)
+
+func FuzzLoadJSON(f *testing.F) {
+	f.Add([]byte(`[{"weight": 3, "value": 4}, {"weight": 1, "value": 2}]`))
+	f.Add([]byte(`{"name": "test", "capacity": 10, "optimum": 6, "items": [{"id": 1, "weight": 3, "value": 4, "quantity": 2}]}`))
+	f.Add([]byte(`{"capacity": 5, "generator": {"family": "uncorrelated", "n": 2}, "items": [{"values": [1, 2]}]}`))
+	f.Add([]byte(`[]`))
+
+	f.Fuzz(func(t *testing.T, content []byte) {
+		// Le chargeur ne doit jamais paniquer, quel que soit le contenu du fichier
+		filename := filepath.Join(t.TempDir(), "data.json")
+		if err := ioutil.WriteFile(filename, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+		tools.LoadDataFromFile(filename)
+
+		doc, err := formats.ReadFormat(bytes.NewReader(content), formats.JSON)
+		if err != nil {
+			return
+		}
+
+		// Un document lu doit pouvoir être réécrit, et sa réécriture relue à l'identique
+		var first, second bytes.Buffer
+		if err := formats.WriteFormat(&first, doc, formats.JSON); err != nil {
+			t.Fatalf("Failed to write a document that was read: %v", err)
+		}
+		again, err := formats.ReadFormat(bytes.NewReader(first.Bytes()), formats.JSON)
+		if err != nil {
+			t.Fatalf("Failed to read back %q: %v", first.String(), err)
+		}
+		if err := formats.WriteFormat(&second, again, formats.JSON); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(first.Bytes(), second.Bytes()) {
+			t.Errorf("Round trip changed the document:\n%s\n%s", first.String(), second.String())
+		}
+	})
+}
+
+func FuzzMerkleHellman(f *testing.F) {
+	privKey, pubKey, err := merkel_hellman.GenerateKeys(2000, 3)
+	if err != nil {
+		f.Fatalf("Failed to generate keys: %v", err)
+	}
+
+	f.Add("Hello, world")
+	f.Add("")
+	f.Add("café")
+	f.Add("\x00\x01\xff")
+
+	f.Fuzz(func(t *testing.T, message string) {
+		c, err := merkel_hellman.Encrypt(pubKey, message)
+		if err != nil {
+			// Caractère hors de l'octet ou message plus long que la clé
+			return
+		}
+
+		decrypted, err := merkel_hellman.Decrypt(privKey, c)
+		if err != nil {
+			t.Fatalf("Failed to decrypt %q: %v", message, err)
+		}
+
+		// Chaque caractère est chiffré sur un octet, et le déchiffrement complète le message par des octets nuls
+		expected := make([]byte, 0, len(message))
+		for _, r := range message {
+			expected = append(expected, byte(r))
+		}
+		if strings.TrimRight(decrypted, "\x00") != strings.TrimRight(string(expected), "\x00") {
+			t.Errorf("Decrypt(Encrypt(%q)) = %q", message, decrypted)
+		}
+	})
+}
@@ -4,6 +4,9 @@ package lll_merkel_hellman
 /* Attention: Activation de l'attaque contre Merkle-Hellman */
 
 import (
+	"context"
+	"errors"
+	"math"
 	"math/big"
 
 	"../algo_reduc_reseau"
@@ -37,3 +40,98 @@ func CryptanalyseMerkleHellman(ciphertext *big.Int, pubKey *merkel_hellman.Publi
 
 	return plaintext, err
 }
+
+var ErrAttackFailed = errors.New("Lattice reduction did not reveal a solution")
+
+/* Density renvoie la densité n / log2(max a_i) d'un sac à dos : les attaques par réseau réussissent aux faibles densités */
+func Density(weights []*big.Int) float64 {
+	bits := 0
+	for _, w := range weights {
+		if w.BitLen() > bits {
+			bits = w.BitLen()
+		}
+	}
+	if bits == 0 {
+		return math.Inf(1)
+	}
+	return float64(len(weights)) / float64(bits)
+}
+
+// LowDensityAttack résout la somme de sous-ensemble somme(x[i]*weights[i]) = target par réduction de réseau
+// (Coster, Joux, LaMacchia, Odlyzko, Schnorr et Stern) : la base formée des lignes (2·e_i, N·a_i) et
+// (1, ..., 1, N·s) contient le vecteur court (1 - 2x_i, ..., 0), que LLL retrouve souvent quand la densité est
+// inférieure à 0,94. Si aucun vecteur de la base réduite ne donne de solution, ErrAttackFailed est renvoyée ;
+// les erreurs de LLLContext sont renvoyées telles quelles, en particulier ErrDependentVectors quand la cible
+// vaut exactement la moitié de la somme des poids (la dernière ligne est alors combinaison des autres).
+func LowDensityAttack(ctx context.Context, weights []*big.Int, target *big.Int) ([]byte, error) {
+	n := len(weights)
+	scale := big.NewInt(int64(math.Ceil(math.Sqrt(float64(n)))) + 1)
+
+	basis := algo_reduc_reseau.CreateMatrix(n+1, n+1)
+	for i, w := range weights {
+		basis[i][i].SetInt64(2)
+		basis[i][n].Mul(scale, w)
+		basis[n][i].SetInt64(1)
+	}
+	basis[n][n].Mul(scale, target)
+
+	reduced, err := algo_reduc_reseau.LLLContext(ctx, basis, big.NewRat(99, 100), math.MaxInt32)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range reduced {
+		if v[n].Sign() != 0 {
+			continue
+		}
+		// Le vecteur et son opposé donnent les deux candidats x_i = (1 - v_i)/2 et x_i = (1 + v_i)/2
+		for _, sign := range []int64{1, -1} {
+			if bits := candidate(v[:n], sign); bits != nil && subsetSum(weights, bits).Cmp(target) == 0 {
+				return bits, nil
+			}
+		}
+	}
+	return nil, ErrAttackFailed
+}
+
+/* candidate convertit un vecteur à coefficients ±1 en vecteur de bits, nil si un coefficient n'est pas ±1 */
+func candidate(v algo_reduc_reseau.Vector, sign int64) []byte {
+	bits := make([]byte, len(v))
+	for i, c := range v {
+		if !c.IsInt64() || (c.Int64() != 1 && c.Int64() != -1) {
+			return nil
+		}
+		if c.Int64()*sign == -1 {
+			bits[i] = 1
+		}
+	}
+	return bits
+}
+
+func subsetSum(weights []*big.Int, bits []byte) *big.Int {
+	sum := new(big.Int)
+	for i, b := range bits {
+		if b == 1 {
+			sum.Add(sum, weights[i])
+		}
+	}
+	return sum
+}
+
+// LowDensityAttackMerkleHellman retrouve le message chiffré c à partir de la seule clé publique par l'attaque
+// de faible densité, contrairement à CryptanalyseMerkleHellman qui a encore besoin de la clé privée. Elle
+// complète subset_sum.AttackMerkleHellman, exacte mais limitée à MaxWeights poids, en acceptant des clés de
+// toute taille tant que leur densité est faible.
+func LowDensityAttackMerkleHellman(ctx context.Context, pubKey *merkel_hellman.PublicKey, c *big.Int) (string, error) {
+	bits, err := LowDensityAttack(ctx, pubKey.M, c)
+	if err != nil {
+		return "", err
+	}
+
+	// Compléter les bits comme Decrypt pour une clé dont la longueur n'est pas un multiple de 8
+	bitPadding := 8 - (len(bits) % 8)
+	if bitPadding < 8 {
+		bits = append(bits, make([]byte, bitPadding)...)
+	}
+	return merkel_hellman.BinaryToString(bits)
+}
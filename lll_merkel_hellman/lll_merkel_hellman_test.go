@@ -0,0 +1,97 @@
+package lll_merkel_hellman_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"../algo_reduc_reseau"
+	"../lll_merkel_hellman"
+	"../merkel_hellman"
+)
+
+func TestLowDensityAttack(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 10; trial++ {
+		weights := make([]*big.Int, 16)
+		target := new(big.Int)
+		for i := range weights {
+			weights[i] = new(big.Int).Rand(random, new(big.Int).Lsh(big.NewInt(1), 48))
+			if random.Intn(2) == 1 {
+				target.Add(target, weights[i])
+			}
+		}
+		if d := lll_merkel_hellman.Density(weights); d > 0.4 {
+			t.Fatalf("Unexpected density %g", d)
+		}
+
+		bits, err := lll_merkel_hellman.LowDensityAttack(context.Background(), weights, target)
+		if err != nil {
+			t.Fatalf("Trial %d: %v", trial, err)
+		}
+		sum := new(big.Int)
+		for i, b := range bits {
+			if b == 1 {
+				sum.Add(sum, weights[i])
+			}
+		}
+		if sum.Cmp(target) != 0 {
+			t.Errorf("Trial %d: subset sums to %s, expected %s", trial, sum, target)
+		}
+	}
+}
+
+func TestLowDensityAttackHalfSum(t *testing.T) {
+	weights := []*big.Int{big.NewInt(3), big.NewInt(5), big.NewInt(11), big.NewInt(21)}
+	_, err := lll_merkel_hellman.LowDensityAttack(context.Background(), weights, big.NewInt(20))
+	if !errors.Is(err, algo_reduc_reseau.ErrDependentVectors) {
+		t.Errorf("Expected ErrDependentVectors for a target of half the total, got %v", err)
+	}
+}
+
+func TestLowDensityAttackMerkleHellman(t *testing.T) {
+	_, pubKey, err := merkel_hellman.GenerateKeys(64, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := merkel_hellman.Encrypt(pubKey, "Go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext, err := lll_merkel_hellman.LowDensityAttackMerkleHellman(context.Background(), pubKey, c)
+	if err != nil {
+		t.Fatalf("Attack failed on a key of density %g: %v", lll_merkel_hellman.Density(pubKey.M), err)
+	}
+	if plaintext != "Go" {
+		t.Errorf("Attack recovered %q, expected %q", plaintext, "Go")
+	}
+}
+
+func TestLowDensityAttackShortKey(t *testing.T) {
+	// 25 octets donnent une clé de 10 poids : les bits retrouvés doivent être complétés jusqu'à 16
+	privKey, pubKey, err := merkel_hellman.GenerateKeys(25, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pubKey.M) != 10 {
+		t.Fatalf("Expected a 10-weight key, got %d weights", len(pubKey.M))
+	}
+	c, err := merkel_hellman.Encrypt(pubKey, "A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := merkel_hellman.Decrypt(privKey, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext, err := lll_merkel_hellman.LowDensityAttackMerkleHellman(context.Background(), pubKey, c)
+	if err != nil {
+		t.Fatalf("Attack failed on a key of density %g: %v", lll_merkel_hellman.Density(pubKey.M), err)
+	}
+	if plaintext != expected {
+		t.Errorf("Attack recovered %q, Decrypt gives %q", plaintext, expected)
+	}
+}
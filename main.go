@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"math/big"
+	"os"
 
 	"./algo_reduc_reseau"
 	"./create_data"
@@ -10,6 +12,15 @@ import (
 )
 
 func main() {
+	// Sous-commandes : bench ; sans argument, le programme exécute la démonstration complète
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1], os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "Erreur :", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	fmt.Println("=========== Début de l'exécution de The-Knapsack-Problem ===========")
 	fmt.Println()
 
@@ -40,7 +51,10 @@ func main() {
 
 	// Appliquer l'algorithme LLL au réseau de Lagarias-Odlyzko
 	fmt.Println("Réduction du réseau de Lagarias-Odlyzko avec LLL...")
-	LOReduced := algo_reduc_reseau.LLL(LONetwork, big.NewRat(3, 4), 1000)
+	LOReduced, err := algo_reduc_reseau.LLLContext(tools.WithProgress(context.Background()), LONetwork, big.NewRat(3, 4), 1000)
+	if err != nil {
+		fmt.Println("Réduction incomplète :", err)
+	}
 	algo_reduc_reseau.PrintMatrix(LOReduced)
 	fmt.Println("Réduction terminée.")
 	fmt.Println()
@@ -54,7 +68,10 @@ func main() {
 
 	// Appliquer l'algorithme LLL au réseau de Joux-Stern
 	fmt.Println("Réduction du réseau de Joux-Stern avec LLL...")
-	JSReduced := algo_reduc_reseau.LLL(JSNetwork, big.NewRat(3, 4), 1000)
+	JSReduced, err := algo_reduc_reseau.LLLContext(tools.WithProgress(context.Background()), JSNetwork, big.NewRat(3, 4), 1000)
+	if err != nil {
+		fmt.Println("Réduction incomplète :", err)
+	}
 	algo_reduc_reseau.PrintMatrix(JSReduced)
 	fmt.Println("Réduction terminée.")
 	fmt.Println()
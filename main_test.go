@@ -1,8 +1,20 @@
 package main_test
 
 import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"./algo_reduc_reseau"
+	"./branch_and_bound"
+	"./common"
+	"./create_data"
+	"./minknap"
+	"./reserch_exhastive"
 	"./tools"
 )
 
@@ -36,3 +48,87 @@ func BenchmarkExhaustiveSearch(b *testing.B) {
 		tools.SolveKnapsackWithExhaustiveSearch(filename, capacity)
 	}
 }
+
+func BenchmarkBranchAndBound(b *testing.B) {
+	data, err := tools.LoadDataFromFile("data.json")
+	if err != nil {
+		b.Fatalf("Failed to load data: %v", err)
+	}
+
+	capacity := 80
+	for n := 0; n < b.N; n++ {
+		branch_and_bound.Knapsack(context.Background(), data, capacity, branch_and_bound.MartelloToth)
+	}
+}
+
+func BenchmarkMinknapStronglyCorrelated(b *testing.B) {
+	inst, _, err := create_data.Generate(create_data.Config{Family: create_data.StronglyCorrelated, N: 10000, Seed: 1})
+	if err != nil {
+		b.Fatalf("Failed to generate instance: %v", err)
+	}
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		minknap.Knapsack(context.Background(), inst.Objects, inst.Capacity, minknap.Options{})
+	}
+}
+
+func TestProgressEvents(t *testing.T) {
+	inst, _, err := create_data.Generate(create_data.Config{Family: create_data.Uncorrelated, N: 20, Seed: 1})
+	if err != nil {
+		t.Fatalf("Failed to generate instance: %v", err)
+	}
+
+	var events []common.Progress
+	ctx := common.WithProgress(context.Background(), 0, func(p common.Progress) { events = append(events, p) })
+	value, _, nodes, _ := reserch_exhastive.KnapsackContext(ctx, inst.Objects, inst.Capacity)
+
+	if len(events) < 2 {
+		t.Fatalf("Expected periodic events, got %d", len(events))
+	}
+	for k := 1; k < len(events); k++ {
+		if events[k].Nodes < events[k-1].Nodes || events[k].Best < events[k-1].Best {
+			t.Errorf("Event %d goes backwards: %+v after %+v", k, events[k], events[k-1])
+		}
+	}
+	last := events[len(events)-1]
+	if !last.Done || last.Nodes != nodes || last.Best != value || last.Solver != "exhaustive" {
+		t.Errorf("Unexpected final event %+v (nodes %d, value %d)", last, nodes, value)
+	}
+
+	events = nil
+	algo_reduc_reseau.LLLContext(ctx, algo_reduc_reseau.GenerateLagariasOdlyzkoNetwork(6), big.NewRat(3, 4), 1000)
+	last = events[len(events)-1]
+	if !last.Done || last.Iteration != len(events)-1 || last.Swaps > last.Iteration {
+		t.Errorf("Unexpected final LLL event %+v after %d events", last, len(events))
+	}
+}
+
+func TestLoadValidatesObjects(t *testing.T) {
+	dir, err := ioutil.TempDir("", "knapsack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "data.json")
+	content := `[{"weight": 4, "value": 5}, {"weight": 0, "value": 7}, {"weight": 6, "value": 0}, {"weight": 12, "value": 9}]`
+	if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var errs common.ValidationErrors
+	if _, err := tools.LoadDataFromFile(filename); !errors.As(err, &errs) || !errs.Has(common.NonPositiveWeight) || !errs.Has(common.NonPositiveValue) {
+		t.Errorf("Expected weight and value errors, got %v", err)
+	}
+	if _, err := tools.LoadInstanceFromFile(filename, 10, nil); !errors.As(err, &errs) || errs.Has(common.TooHeavy) {
+		t.Errorf("Expected validation errors without TooHeavy, got %v", err)
+	}
+
+	loaded, err := tools.LoadInstanceFromFile(filename, 10, &common.DefaultNormalizeOptions)
+	if err != nil {
+		t.Fatalf("Normalised instance should be valid: %v", err)
+	}
+	if len(loaded.Instance.Objects) != 1 || len(loaded.Fixed) != 1 {
+		t.Errorf("Unexpected normalised instance %+v", loaded)
+	}
+}
@@ -0,0 +1,143 @@
+package meet_in_the_middle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"../algorithme_glouton"
+	"../common"
+)
+
+/* MaxObjects est le nombre maximal d'objets, chaque sous-ensemble étant codé sur un uint64 */
+const MaxObjects = 64
+
+/* state représente un sous-ensemble d'une moitié des objets */
+type state struct {
+	weight int
+	value  int
+	mask   uint64
+}
+
+// Knapsack résout le problème du sac à dos par la méthode de Horowitz-Sahni : les deux moitiés des objets
+// sont énumérées séparément, les sous-ensembles dominés sont éliminés, puis les deux listes sont fusionnées.
+// Retourne la meilleure valeur, les indices des objets choisis, la taille totale des deux listes et si le contexte
+// a interrompu l'énumération : les listes partielles sont alors fusionnées quand même, ce qui donne la meilleure
+// solution parmi les objets déjà énumérés.
+func Knapsack(ctx context.Context, objects []common.Objects, capacity int) (int, []int, int64, bool, error) {
+	n := len(objects)
+	if n > MaxObjects {
+		return 0, nil, 0, false, fmt.Errorf("Meet-in-the-middle supports at most %d objects, got %d", MaxObjects, n)
+	}
+	if capacity < 0 {
+		return 0, nil, 0, false, fmt.Errorf("Invalid capacity %d", capacity)
+	}
+
+	left, interrupted := enumerate(ctx, objects, 0, n/2, capacity)
+	right := []state{{}}
+	if !interrupted {
+		right, interrupted = enumerate(ctx, objects, n/2, n, capacity)
+	}
+
+	// Pour chaque sous-ensemble de gauche (poids croissant), le meilleur complément à droite
+	// est le plus lourd qui rentre encore : les valeurs y sont croissantes avec le poids.
+	best := state{}
+	j := len(right) - 1
+	for _, a := range left {
+		for j >= 0 && a.weight+right[j].weight > capacity {
+			j--
+		}
+		if j < 0 {
+			break
+		}
+		if a.value+right[j].value > best.value {
+			best = state{a.weight + right[j].weight, a.value + right[j].value, a.mask | right[j].mask}
+		}
+	}
+
+	indices := make([]int, 0)
+	for i := 0; i < n; i++ {
+		if best.mask&(1<<uint(i)) != 0 {
+			indices = append(indices, i)
+		}
+	}
+
+	return best.value, indices, int64(len(left) + len(right)), interrupted, nil
+}
+
+// enumerate construit la liste des sous-ensembles non dominés des objets [from, to), triée par poids croissant.
+// Chaque objet est ajouté en fusionnant la liste courante avec sa copie décalée, comme dans Horowitz-Sahni.
+// Si le contexte est annulé, la liste des objets déjà ajoutés est renvoyée avec le second résultat à vrai.
+func enumerate(ctx context.Context, objects []common.Objects, from, to, capacity int) ([]state, bool) {
+	list := []state{{}}
+
+	for i := from; i < to; i++ {
+		if ctx.Err() != nil {
+			return list, true
+		}
+		obj := objects[i]
+		bit := uint64(1) << uint(i)
+
+		shifted := make([]state, 0, len(list))
+		for _, s := range list {
+			if s.weight+obj.Weight <= capacity {
+				shifted = append(shifted, state{s.weight + obj.Weight, s.value + obj.Value, s.mask | bit})
+			}
+		}
+
+		list = mergeNonDominated(list, shifted)
+	}
+
+	return list, false
+}
+
+// mergeNonDominated fusionne deux listes triées par poids en ne gardant que les sous-ensembles
+// dont la valeur est strictement supérieure à celle de tous les sous-ensembles plus légers
+func mergeNonDominated(a, b []state) []state {
+	merged := make([]state, 0, len(a)+len(b))
+	i, j := 0, 0
+
+	for i < len(a) || j < len(b) {
+		var next state
+		if j == len(b) || (i < len(a) && (a[i].weight < b[j].weight || (a[i].weight == b[j].weight && a[i].value >= b[j].value))) {
+			next = a[i]
+			i++
+		} else {
+			next = b[j]
+			j++
+		}
+
+		if len(merged) == 0 || next.value > merged[len(merged)-1].value {
+			merged = append(merged, next)
+		}
+	}
+
+	return merged
+}
+
+/* Solver adapte la méthode de Horowitz-Sahni à l'interface common.Solver */
+type Solver struct{}
+
+func init() {
+	common.Register(Solver{})
+}
+
+func (Solver) Name() string {
+	return "meet_in_the_middle"
+}
+
+func (Solver) Solve(ctx context.Context, inst common.Instance) (common.Result, error) {
+	startTime := time.Now()
+	_, indices, size, interrupted, err := Knapsack(ctx, inst.Objects, inst.Capacity)
+	if err != nil {
+		return common.Result{}, err
+	}
+
+	res := common.NewResult(inst, indices, true)
+	if interrupted {
+		res.Interrupt(algorithme_glouton.FractionalKnapsack(inst.Objects, inst.Capacity).Bound)
+	}
+	res.Stats.Nodes = size
+	res.Stats.Duration = time.Since(startTime)
+	return res, nil
+}
@@ -0,0 +1,43 @@
+package meet_in_the_middle_test
+
+import (
+	"context"
+	"testing"
+
+	"../algo_prog_dynamique"
+	"../common"
+	"../create_data"
+	"../meet_in_the_middle"
+)
+
+func TestAgainstDynamicProgramming(t *testing.T) {
+	for _, family := range create_data.Families() {
+		for _, n := range []int{0, 1, 7, 24} {
+			inst, _, err := create_data.Generate(create_data.Config{Family: family, N: n, Range: 1000, Seed: int64(n + 1)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			value, indices, _, interrupted, err := meet_in_the_middle.Knapsack(context.Background(), inst.Objects, inst.Capacity)
+			if err != nil || interrupted {
+				t.Fatalf("%s n=%d: interrupted %t, %v", family, n, interrupted, err)
+			}
+			res := common.NewResult(inst, indices, true)
+			if res.Value != value || res.Weight > inst.Capacity {
+				t.Errorf("%s n=%d: indices give value %d and weight %d, announced %d for capacity %d", family, n, res.Value, res.Weight, value, inst.Capacity)
+			}
+			if optimum := algo_prog_dynamique.KnapsackValue(inst.Objects, inst.Capacity); value != optimum {
+				t.Errorf("%s n=%d: value %d, optimum %d", family, n, value, optimum)
+			}
+		}
+	}
+}
+
+func TestTooManyObjects(t *testing.T) {
+	inst, _, err := create_data.Generate(create_data.Config{N: meet_in_the_middle.MaxObjects + 1, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, _, err := meet_in_the_middle.Knapsack(context.Background(), inst.Objects, inst.Capacity); err == nil {
+		t.Errorf("Expected an error for %d objects", len(inst.Objects))
+	}
+}
@@ -0,0 +1,96 @@
+package merkel_hellman_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"../merkel_hellman"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	for _, s := range []string{"", "a", "Go!", "sac à dos"} {
+		bits, err := merkel_hellman.StringToBinary(s)
+		if err != nil {
+			t.Fatalf("%q: %v", s, err)
+		}
+		back, err := merkel_hellman.BinaryToString(bits)
+		if err != nil {
+			t.Fatalf("%q: %v", s, err)
+		}
+		// Les caractères au-delà de l'ASCII sont codés sur un octet, comme en Latin-1
+		if back != string(latin1(s)) {
+			t.Errorf("%q: got back %q", s, back)
+		}
+	}
+
+	if _, err := merkel_hellman.StringToBinary("€"); err == nil {
+		t.Error("Expected an error for a character above 255")
+	}
+	if _, err := merkel_hellman.BinaryToString([]byte{1, 0, 1}); err == nil {
+		t.Error("Expected an error for a bit sequence whose length is not a multiple of 8")
+	}
+}
+
+func latin1(s string) []byte {
+	var b []byte
+	for _, c := range s {
+		b = append(b, byte(c))
+	}
+	return b
+}
+
+func TestSuperIncreasingSequence(t *testing.T) {
+	r, err := merkel_hellman.GenerateSuperIncreasingSequence(20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := new(big.Int)
+	for i, ri := range r {
+		if ri.Cmp(sum) <= 0 {
+			t.Fatalf("Element %d (%s) is not above the sum of the previous ones (%s)", i, ri, sum)
+		}
+		sum.Add(sum, ri)
+	}
+
+	if _, err := merkel_hellman.GenerateSuperIncreasingSequence(1); err == nil {
+		t.Error("Expected an error for a one-element sequence")
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	for _, iterations := range []int{1, 3} {
+		// 64 octets donnent une clé de 16 poids, de quoi chiffrer deux caractères
+		privKey, pubKey, err := merkel_hellman.GenerateKeys(64, iterations)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, message := range []string{"Go", "ok", "a"} {
+			c, err := merkel_hellman.Encrypt(pubKey, message)
+			if err != nil {
+				t.Fatalf("%q: %v", message, err)
+			}
+			plaintext, err := merkel_hellman.Decrypt(privKey, c)
+			if err != nil {
+				t.Fatalf("%q: %v", message, err)
+			}
+			// Un message plus court que la clé est complété par des octets nuls
+			if strings.TrimRight(plaintext, "\x00") != message {
+				t.Errorf("%d iterations: decrypted %q, expected %q", iterations, plaintext, message)
+			}
+		}
+
+		if _, err := merkel_hellman.Encrypt(pubKey, "long"); err == nil {
+			t.Error("Expected an error for a message longer than the key")
+		}
+	}
+}
+
+func TestInvalidKeys(t *testing.T) {
+	if _, _, err := merkel_hellman.GenerateKeys(1, 1); err == nil {
+		t.Error("Expected an error for a one-byte key")
+	}
+	if _, _, err := merkel_hellman.GenerateKeys(64, 0); err == nil {
+		t.Error("Expected an error for zero iterations")
+	}
+}
@@ -0,0 +1,347 @@
+package minknap
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"../common"
+)
+
+/* coreSize est la taille en dessous de laquelle un intervalle est trié entièrement */
+const coreSize = 32
+
+/* Options paramètre la recherche */
+type Options struct {
+	MaxStates int64 // nombre maximal d'états engendrés, 0 : pas de limite
+}
+
+/* item est un objet utile (poids et valeur strictement positifs) et son indice d'origine */
+type item struct {
+	p, w, i int
+}
+
+/* better ordonne les objets par rapport valeur/poids décroissant, l'indice départageant les égalités */
+func better(a, b item) bool {
+	if a.p*b.w != b.p*a.w {
+		return a.p*b.w > b.p*a.w
+	}
+	return a.i < b.i
+}
+
+/* interval est un intervalle [f, l] d'objets mieux (ou moins bien) classés que le noyau, mais pas encore triés */
+type interval struct {
+	f, l int
+}
+
+/* state est une solution qui ne diffère de la solution critique que sur les objets du noyau ; les objets changés sont retrouvés en remontant les parents */
+type state struct {
+	w, p   int
+	pos    int // position de l'objet changé en dernier, -1 pour la solution critique
+	parent *state
+}
+
+/* search contient l'état de l'algorithme */
+type search struct {
+	items    []item
+	capacity int
+
+	// Le noyau [lo, hi] est trié ; heads (à gauche) et tails (à droite) sont les intervalles non triés, le plus
+	// proche du noyau au sommet de la pile
+	lo, hi       int
+	heads, tails []interval
+
+	b      int // objet critique : les objets 0..b-1 forment la solution critique
+	s, t   int // les objets s..t ont été ajoutés à la programmation dynamique
+	states []*state
+	best   *state
+	nodes  int64
+}
+
+// Knapsack résout le problème du sac à dos 0-1 par un algorithme à noyau dans l'esprit de minknap (Pisinger) :
+// l'objet critique est trouvé par partitions successives autour d'un pivot, sans trier toute l'instance, puis
+// une programmation dynamique part de la solution critique et agrandit le noyau un objet à la fois, en
+// alternant l'ajout d'un objet après l'objet critique et le retrait d'un objet avant lui. Les intervalles d'objets
+// ne sont triés que lorsque le noyau les atteint, et les états dominés ou dont la borne ne dépasse pas la
+// meilleure valeur connue sont éliminés à chaque étape : l'algorithme s'arrête dès qu'il n'en reste plus, en
+// général bien avant d'avoir examiné les objets éloignés de l'objet critique. Retourne la valeur, les indices
+// choisis, la borne supérieure (égale à la valeur si la solution est optimale), le nombre d'états engendrés et
+// si l'optimalité est prouvée (faux si opts.MaxStates a été atteint ou le contexte annulé).
+func Knapsack(ctx context.Context, objects []common.Objects, capacity int, opts Options) (int, []int, int, int64, bool, error) {
+	if capacity < 0 {
+		return 0, nil, 0, 0, false, fmt.Errorf("Invalid capacity %d", capacity)
+	}
+
+	// Les objets de poids nul et de valeur positive sont toujours pris, les objets inutiles ou trop lourds écartés
+	s := &search{capacity: capacity}
+	indices := make([]int, 0)
+	fixedValue, totalWeight, totalValue := 0, 0, 0
+	for i, obj := range objects {
+		switch {
+		case obj.Weight < 0:
+			return 0, nil, 0, 0, false, fmt.Errorf("Object %d has negative weight %d", i, obj.Weight)
+		case obj.Value <= 0 || obj.Weight > capacity:
+		case obj.Weight == 0:
+			indices = append(indices, i)
+			fixedValue += obj.Value
+		default:
+			s.items = append(s.items, item{p: obj.Value, w: obj.Weight, i: i})
+			totalWeight += obj.Weight
+			totalValue += obj.Value
+		}
+	}
+
+	if totalWeight <= capacity {
+		for _, it := range s.items {
+			indices = append(indices, it.i)
+		}
+		sort.Ints(indices)
+		value := fixedValue + totalValue
+		return value, indices, value, 0, true, nil
+	}
+
+	ws, ps := s.findBreak()
+	s.best = &state{w: ws, p: ps, pos: -1}
+	s.states = []*state{s.best}
+	s.s, s.t = s.b, s.b-1
+
+	// Agrandir le noyau tant qu'il reste des états susceptibles d'améliorer la meilleure solution
+	optimal := true
+	progress := common.NewReporter(ctx)
+	for len(s.states) > 0 {
+		if (opts.MaxStates > 0 && s.nodes > opts.MaxStates) || ctx.Err() != nil {
+			optimal = false
+			break
+		}
+		if progress.Due() {
+			progress.Report(common.Progress{Solver: "minknap", Nodes: s.nodes, Best: fixedValue + s.best.p})
+		}
+		addNext := s.sorted(s.t + 1)
+		removeNext := s.sorted(s.s - 1)
+		if !addNext && !removeNext {
+			break
+		}
+		if addNext {
+			s.t++
+			s.expand(s.t, 1)
+			s.reduce()
+		}
+		if removeNext && len(s.states) > 0 {
+			s.s--
+			s.expand(s.s, -1)
+			s.reduce()
+		}
+	}
+
+	// Les objets changés par rapport à la solution critique sont ceux rencontrés en remontant les parents
+	changed := make(map[int]bool)
+	for st := s.best; st.pos >= 0; st = st.parent {
+		changed[st.pos] = true
+	}
+	for pos, it := range s.items {
+		if (pos < s.b) != changed[pos] {
+			indices = append(indices, it.i)
+		}
+	}
+	sort.Ints(indices)
+
+	bound := s.best.p
+	if !optimal {
+		for _, st := range s.states {
+			if ub := s.upperBound(st); ub > bound {
+				bound = ub
+			}
+		}
+	}
+	progress.Finish(common.Progress{Solver: "minknap", Nodes: s.nodes, Best: fixedValue + s.best.p})
+	return fixedValue + s.best.p, indices, fixedValue + bound, s.nodes, optimal, nil
+}
+
+// findBreak trouve l'objet critique par partitions successives : à chaque étape, les objets sont séparés autour
+// d'un pivot, la partie qui contient l'objet critique est conservée et l'autre est empilée sans être triée.
+// Retourne le poids et la valeur de la solution critique.
+func (s *search) findBreak() (int, int) {
+	f, l := 0, len(s.items)-1
+	ws, ps := 0, 0
+
+	// Invariant : ws + poids(f..l) > capacité, donc l'objet critique est dans [f, l]
+	for l-f+1 > coreSize {
+		m := s.partition(f, l)
+		wl, pl := 0, 0
+		for pos := f; pos < m; pos++ {
+			wl += s.items[pos].w
+			pl += s.items[pos].p
+		}
+
+		switch {
+		case ws+wl > s.capacity:
+			s.tails = append(s.tails, interval{m, l})
+			l = m - 1
+		case ws+wl+s.items[m].w > s.capacity:
+			if m > f {
+				s.heads = append(s.heads, interval{f, m - 1})
+			}
+			if m < l {
+				s.tails = append(s.tails, interval{m + 1, l})
+			}
+			ws, ps = ws+wl, ps+pl
+			f, l = m, m
+		default:
+			s.heads = append(s.heads, interval{f, m})
+			ws, ps = ws+wl+s.items[m].w, ps+pl+s.items[m].p
+			f = m + 1
+		}
+	}
+
+	s.sortRange(f, l)
+	s.lo, s.hi = f, l
+	s.b = f
+	for ws+s.items[s.b].w <= s.capacity {
+		ws += s.items[s.b].w
+		ps += s.items[s.b].p
+		s.b++
+	}
+	return ws, ps
+}
+
+/* partition place à sa position finale un pivot (médiane de trois), les objets meilleurs à sa gauche, et renvoie sa position */
+func (s *search) partition(f, l int) int {
+	items := s.items
+	mid := f + (l-f)/2
+	if better(items[mid], items[f]) {
+		items[mid], items[f] = items[f], items[mid]
+	}
+	if better(items[l], items[f]) {
+		items[l], items[f] = items[f], items[l]
+	}
+	if better(items[l], items[mid]) {
+		items[l], items[mid] = items[mid], items[l]
+	}
+	items[f], items[mid] = items[mid], items[f]
+
+	pivot := items[f]
+	store := f + 1
+	for pos := f + 1; pos <= l; pos++ {
+		if better(items[pos], pivot) {
+			items[pos], items[store] = items[store], items[pos]
+			store++
+		}
+	}
+	items[f], items[store-1] = items[store-1], items[f]
+	return store - 1
+}
+
+func (s *search) sortRange(f, l int) {
+	part := s.items[f : l+1]
+	sort.Slice(part, func(a, b int) bool { return better(part[a], part[b]) })
+}
+
+/* sorted étend le noyau si nécessaire pour que la position pos soit triée, et indique si elle existe */
+func (s *search) sorted(pos int) bool {
+	for pos < s.lo && len(s.heads) > 0 {
+		top := s.heads[len(s.heads)-1]
+		s.heads = s.heads[:len(s.heads)-1]
+		s.sortRange(top.f, top.l)
+		s.lo = top.f
+	}
+	for pos > s.hi && len(s.tails) > 0 {
+		top := s.tails[len(s.tails)-1]
+		s.tails = s.tails[:len(s.tails)-1]
+		s.sortRange(top.f, top.l)
+		s.hi = top.l
+	}
+	return pos >= 0 && pos < len(s.items)
+}
+
+// expand ajoute l'objet en position pos à la programmation dynamique : chaque état est conservé tel quel ou
+// changé de sign fois l'objet (ajouté après l'objet critique, retiré avant lui). Les deux listes, triées par
+// poids croissant, sont fusionnées en éliminant les états dominés (plus lourds et pas plus précieux).
+func (s *search) expand(pos, sign int) {
+	it := s.items[pos]
+	changed := make([]*state, len(s.states))
+	for k, st := range s.states {
+		changed[k] = &state{w: st.w + sign*it.w, p: st.p + sign*it.p, pos: pos, parent: st}
+		if changed[k].w <= s.capacity && changed[k].p > s.best.p {
+			s.best = changed[k]
+		}
+	}
+	s.nodes += int64(len(changed))
+
+	merged := make([]*state, 0, 2*len(s.states))
+	a, b := s.states, changed
+	for len(a) > 0 || len(b) > 0 {
+		var next *state
+		if len(b) == 0 || (len(a) > 0 && (a[0].w < b[0].w || (a[0].w == b[0].w && a[0].p >= b[0].p))) {
+			next, a = a[0], a[1:]
+		} else {
+			next, b = b[0], b[1:]
+		}
+		if len(merged) == 0 || next.p > merged[len(merged)-1].p {
+			merged = append(merged, next)
+		}
+	}
+	s.states = merged
+}
+
+// upperBound renvoie une borne de la valeur des solutions issues d'un état : un état réalisable ne peut plus que
+// gagner des objets de rapport au plus celui de l'objet t+1, un état irréalisable doit perdre des objets de
+// rapport au moins celui de l'objet s-1.
+func (s *search) upperBound(st *state) int {
+	if st.w <= s.capacity {
+		if !s.sorted(s.t + 1) {
+			return st.p
+		}
+		it := s.items[s.t+1]
+		return st.p + (s.capacity-st.w)*it.p/it.w
+	}
+	if !s.sorted(s.s - 1) {
+		return -1
+	}
+	it := s.items[s.s-1]
+	return st.p - ((st.w-s.capacity)*it.p+it.w-1)/it.w
+}
+
+/* reduce élimine les états dont la borne ne dépasse pas la meilleure valeur connue */
+func (s *search) reduce() {
+	kept := s.states[:0]
+	for _, st := range s.states {
+		if s.upperBound(st) > s.best.p {
+			kept = append(kept, st)
+		}
+	}
+	s.states = kept
+}
+
+/* Solver adapte l'algorithme à noyau à l'interface common.Solver */
+type Solver struct {
+	Options Options
+}
+
+func init() {
+	common.Register(Solver{})
+}
+
+func (Solver) Name() string {
+	return "minknap"
+}
+
+func (s Solver) Solve(ctx context.Context, inst common.Instance) (common.Result, error) {
+	startTime := time.Now()
+	_, indices, bound, nodes, optimal, err := Knapsack(ctx, inst.Objects, inst.Capacity, s.Options)
+	if err != nil {
+		return common.Result{}, err
+	}
+
+	res := common.NewResult(inst, indices, optimal)
+	res.Bound = bound
+	if !optimal && ctx.Err() != nil {
+		res.Interrupt(bound)
+	}
+	if res.Optimal {
+		res.Certificate = common.DualCertificate(inst, res.Value)
+	}
+	res.Stats.Nodes = nodes
+	res.Stats.Duration = time.Since(startTime)
+	return res, nil
+}
@@ -0,0 +1,77 @@
+package minknap_test
+
+import (
+	"context"
+	"testing"
+
+	"../algo_prog_dynamique"
+	"../common"
+	"../create_data"
+	"../minknap"
+	"../verification"
+)
+
+func TestAgainstDynamicProgramming(t *testing.T) {
+	for _, family := range create_data.Families() {
+		for _, n := range []int{1, 10, 50, 200} {
+			for seed := int64(1); seed <= 3; seed++ {
+				inst, _, err := create_data.Generate(create_data.Config{Family: family, N: n, Range: 1000, Seed: seed})
+				if err != nil {
+					t.Fatal(err)
+				}
+				res, err := minknap.Solver{}.Solve(context.Background(), inst)
+				if err != nil {
+					t.Fatalf("%s n=%d seed %d: %v", family, n, seed, err)
+				}
+				if err := verification.Verify(inst, res); err != nil {
+					t.Errorf("%s n=%d seed %d: %v", family, n, seed, err)
+				}
+				if optimum := algo_prog_dynamique.KnapsackValue(inst.Objects, inst.Capacity); !res.Optimal || res.Value != optimum {
+					t.Errorf("%s n=%d seed %d: value %d (optimal: %t), optimum %d", family, n, seed, res.Value, res.Optimal, optimum)
+				}
+			}
+		}
+	}
+}
+
+func TestMaxStates(t *testing.T) {
+	inst, _, err := create_data.Generate(create_data.Config{Family: create_data.Families()[len(create_data.Families())-1], N: 200, Range: 1000, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := minknap.Solver{Options: minknap.Options{MaxStates: 1}}.Solve(context.Background(), inst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := verification.Check(inst, res); err != nil {
+		t.Error(err)
+	}
+	optimum := algo_prog_dynamique.KnapsackValue(inst.Objects, inst.Capacity)
+	if res.Optimal && res.Value != optimum {
+		t.Errorf("Claims optimality with value %d, optimum %d", res.Value, optimum)
+	}
+	if !res.Optimal && (res.Bound < optimum || res.Stats.Interrupted) {
+		t.Errorf("State limit: bound %d below optimum %d, or reported as interrupted: %+v", res.Bound, optimum, res.Stats)
+	}
+}
+
+func TestSpecialObjects(t *testing.T) {
+	inst := common.Instance{Objects: []common.Objects{
+		{Weight: 0, Value: 3},  // toujours pris
+		{Weight: 20, Value: 9}, // trop lourd
+		{Weight: 2, Value: 0},  // sans valeur
+		{Weight: 4, Value: 5},
+		{Weight: 7, Value: 8},
+	}, Capacity: 10}
+	res, err := minknap.Solver{}.Solve(context.Background(), inst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Value != 11 || !res.Optimal {
+		t.Errorf("Expected optimal value 11, got %+v", res)
+	}
+
+	if _, err := (minknap.Solver{}).Solve(context.Background(), common.Instance{Capacity: -1}); err == nil {
+		t.Errorf("Expected an error for a negative capacity")
+	}
+}
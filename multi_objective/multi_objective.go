@@ -0,0 +1,223 @@
+package multi_objective
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"../common"
+)
+
+/* Options paramètre le calcul du front de Pareto */
+type Options struct {
+	MaxFront int // taille maximale du front (y compris pendant le calcul), au-delà il est réduit ; 0 : pas de limite
+}
+
+/* Solution est une solution Pareto-optimale : aucun autre sous-ensemble n'est plus léger et meilleur sur tous les critères */
+type Solution struct {
+	Weight  int
+	Values  []int
+	Indices []int
+}
+
+/* state est une solution partielle ; les objets choisis sont retrouvés en remontant les parents */
+type state struct {
+	weight int
+	values []int
+	item   int
+	parent *state
+}
+
+// ParetoFront calcule l'ensemble complet des solutions Pareto-optimales (poids, valeur1, valeur2, ...) par
+// l'algorithme de Nemhauser-Ullmann : les objets sont ajoutés un à un, la liste courante est fusionnée avec sa
+// copie contenant le nouvel objet et les solutions dominées sont éliminées. Les critères de chaque objet sont
+// donnés par Objects.Criteria() et doivent être en même nombre pour tous les objets. Un front partiel n'ayant
+// pas de sens, l'annulation du contexte renvoie seulement son erreur.
+//
+// Si le front dépasse opts.MaxFront solutions, il est réduit aux plus isolées selon la distance de
+// surpeuplement (crowding distance de NSGA-II), qui garde les extrémités et répartit les autres le long du
+// front, et le booléen renvoyé est vrai : les solutions restent réalisables et non dominées entre elles, mais
+// le front n'est plus qu'une approximation, certaines solutions Pareto-optimales pouvant manquer ou être
+// dominées par d'autres qui ont été écartées en cours de calcul.
+func ParetoFront(ctx context.Context, objects []common.Objects, capacity int, opts Options) ([]Solution, bool, error) {
+	if capacity < 0 {
+		return nil, false, fmt.Errorf("Invalid capacity %d", capacity)
+	}
+
+	d := 1
+	if len(objects) > 0 {
+		d = len(objects[0].Criteria())
+	}
+	for i, obj := range objects {
+		if len(obj.Criteria()) != d {
+			return nil, false, fmt.Errorf("Object %d has %d criteria, expected %d", i, len(obj.Criteria()), d)
+		}
+	}
+
+	front := []*state{{values: make([]int, d), item: -1}}
+	truncated := false
+	for i, obj := range objects {
+		if err := ctx.Err(); err != nil {
+			return nil, false, err
+		}
+		criteria := obj.Criteria()
+		candidates := make([]*state, 0, 2*len(front))
+		candidates = append(candidates, front...)
+		for _, s := range front {
+			if s.weight+obj.Weight <= capacity {
+				values := make([]int, d)
+				for k := range values {
+					values[k] = s.values[k] + criteria[k]
+				}
+				candidates = append(candidates, &state{weight: s.weight + obj.Weight, values: values, item: i, parent: s})
+			}
+		}
+
+		front = nonDominated(candidates)
+		if opts.MaxFront > 0 && len(front) > opts.MaxFront {
+			front = crowded(front, opts.MaxFront)
+			truncated = true
+		}
+	}
+
+	solutions := make([]Solution, len(front))
+	for k, s := range front {
+		solutions[k] = Solution{Weight: s.weight, Values: s.values, Indices: make([]int, 0)}
+		for p := s; p.item >= 0; p = p.parent {
+			solutions[k].Indices = append(solutions[k].Indices, p.item)
+		}
+		sort.Ints(solutions[k].Indices)
+	}
+	return solutions, truncated, nil
+}
+
+// crowded garde les size solutions du front de plus grande distance de surpeuplement : pour chaque critère (le
+// poids compris), les deux solutions extrêmes ont une distance infinie et les autres ajoutent l'écart entre
+// leurs voisines, rapporté à l'étendue du critère. L'ordre du front (par poids croissant) est conservé.
+func crowded(front []*state, size int) []*state {
+	n := len(front)
+	objective := func(s *state, k int) float64 {
+		if k == 0 {
+			return float64(s.weight)
+		}
+		return float64(s.values[k-1])
+	}
+
+	distance := make([]float64, n)
+	order := make([]int, n)
+	for k := 0; k <= len(front[0].values); k++ {
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool { return objective(front[order[a]], k) < objective(front[order[b]], k) })
+
+		distance[order[0]], distance[order[n-1]] = math.Inf(1), math.Inf(1)
+		lo, hi := objective(front[order[0]], k), objective(front[order[n-1]], k)
+		if hi == lo {
+			continue
+		}
+		for j := 1; j < n-1; j++ {
+			distance[order[j]] += (objective(front[order[j+1]], k) - objective(front[order[j-1]], k)) / (hi - lo)
+		}
+	}
+
+	// Les égalités (en particulier entre extrémités) sont départagées par l'ordre du front
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return distance[order[a]] > distance[order[b]] })
+	keep := make([]bool, n)
+	for _, i := range order[:size] {
+		keep[i] = true
+	}
+
+	kept := make([]*state, 0, size)
+	for i, s := range front {
+		if keep[i] {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
+
+/* dominates indique si les valeurs a sont au moins aussi bonnes que b sur tous les critères */
+func dominates(a, b []int) bool {
+	for k := range a {
+		if a[k] < b[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// nonDominated trie les solutions par poids croissant (puis par valeurs décroissantes) et ne garde que celles
+// qu'aucune solution plus légère ou de même poids ne domine ; parmi des solutions identiques, une seule est gardée.
+func nonDominated(states []*state) []*state {
+	sort.SliceStable(states, func(a, b int) bool {
+		if states[a].weight != states[b].weight {
+			return states[a].weight < states[b].weight
+		}
+		for k := range states[a].values {
+			if states[a].values[k] != states[b].values[k] {
+				return states[a].values[k] > states[b].values[k]
+			}
+		}
+		return false
+	})
+
+	kept := make([]*state, 0, len(states))
+	for _, s := range states {
+		dominated := false
+		for _, t := range kept {
+			if dominates(t.values, s.values) {
+				dominated = true
+				break
+			}
+		}
+		if !dominated {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
+
+/* WriteCSV exporte le front au format CSV : poids, une colonne par critère, puis les indices des objets séparés par des espaces */
+func WriteCSV(w io.Writer, front []Solution) error {
+	writer := csv.NewWriter(w)
+
+	d := 0
+	if len(front) > 0 {
+		d = len(front[0].Values)
+	}
+	header := []string{"weight"}
+	for k := 1; k <= d; k++ {
+		header = append(header, "value"+strconv.Itoa(k))
+	}
+	header = append(header, "items")
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, s := range front {
+		record := []string{strconv.Itoa(s.Weight)}
+		for _, v := range s.Values {
+			record = append(record, strconv.Itoa(v))
+		}
+		items := make([]string, len(s.Indices))
+		for k, i := range s.Indices {
+			items[k] = strconv.Itoa(i)
+		}
+		record = append(record, strings.Join(items, " "))
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,184 @@
+package multi_objective_test
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"../common"
+	"../multi_objective"
+)
+
+/* instance tire n objets à deux critères */
+func instance(random *rand.Rand, n int) ([]common.Objects, int) {
+	objects := make([]common.Objects, n)
+	total := 0
+	for i := range objects {
+		objects[i] = common.Objects{Weight: 1 + random.Intn(20), Values: []int{random.Intn(30), random.Intn(30)}}
+		total += objects[i].Weight
+	}
+	return objects, total / 2
+}
+
+/* key décrit une solution par son poids et ses valeurs */
+func key(weight int, values []int) [3]int {
+	return [3]int{weight, values[0], values[1]}
+}
+
+// bruteForce énumère tous les sous-ensembles réalisables et garde ceux qu'aucun autre ne domine (au plus lourd
+// et au moins aussi bon partout, avec au moins une inégalité stricte), une seule fois par point.
+func bruteForce(objects []common.Objects, capacity int) [][3]int {
+	var points [][3]int
+	for mask := 0; mask < 1<<len(objects); mask++ {
+		p := [3]int{}
+		for i, obj := range objects {
+			if mask&(1<<i) != 0 {
+				p[0] += obj.Weight
+				p[1] += obj.Values[0]
+				p[2] += obj.Values[1]
+			}
+		}
+		if p[0] <= capacity {
+			points = append(points, p)
+		}
+	}
+
+	front := make(map[[3]int]bool)
+	for _, p := range points {
+		dominated := false
+		for _, q := range points {
+			if q != p && q[0] <= p[0] && q[1] >= p[1] && q[2] >= p[2] {
+				dominated = true
+				break
+			}
+		}
+		if !dominated {
+			front[p] = true
+		}
+	}
+
+	result := make([][3]int, 0, len(front))
+	for p := range front {
+		result = append(result, p)
+	}
+	sortPoints(result)
+	return result
+}
+
+func sortPoints(points [][3]int) {
+	sort.Slice(points, func(a, b int) bool {
+		for k := range points[a] {
+			if points[a][k] != points[b][k] {
+				return points[a][k] < points[b][k]
+			}
+		}
+		return false
+	})
+}
+
+/* checkSolution vérifie que les indices d'une solution donnent son poids et ses valeurs */
+func checkSolution(t *testing.T, objects []common.Objects, capacity int, s multi_objective.Solution) {
+	t.Helper()
+	weight, values := 0, []int{0, 0}
+	for _, i := range s.Indices {
+		weight += objects[i].Weight
+		values[0] += objects[i].Values[0]
+		values[1] += objects[i].Values[1]
+	}
+	if weight != s.Weight || !reflect.DeepEqual(values, s.Values) || weight > capacity {
+		t.Errorf("Solution %+v does not match its items (weight %d, values %v, capacity %d)", s, weight, values, capacity)
+	}
+}
+
+func TestParetoFrontAgainstBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 50; trial++ {
+		objects, capacity := instance(random, 1+random.Intn(10))
+		front, truncated, err := multi_objective.ParetoFront(context.Background(), objects, capacity, multi_objective.Options{})
+		if err != nil || truncated {
+			t.Fatalf("Trial %d: truncated %t, error %v", trial, truncated, err)
+		}
+
+		points := make([][3]int, len(front))
+		for k, s := range front {
+			checkSolution(t, objects, capacity, s)
+			points[k] = key(s.Weight, s.Values)
+		}
+		sortPoints(points)
+		if expected := bruteForce(objects, capacity); !reflect.DeepEqual(points, expected) {
+			t.Errorf("Trial %d: front %v, expected %v", trial, points, expected)
+		}
+	}
+}
+
+func TestParetoFrontTruncation(t *testing.T) {
+	random := rand.New(rand.NewSource(2))
+	objects, capacity := instance(random, 14)
+	full, _, err := multi_objective.ParetoFront(context.Background(), objects, capacity, multi_objective.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const size = 8
+	if len(full) <= size {
+		t.Fatalf("Front of %d solutions is too small for the test", len(full))
+	}
+
+	front, truncated, err := multi_objective.ParetoFront(context.Background(), objects, capacity, multi_objective.Options{MaxFront: size})
+	if err != nil || !truncated {
+		t.Fatalf("Expected a truncated front, got truncated %t, error %v", truncated, err)
+	}
+	if len(front) != size {
+		t.Errorf("Expected %d solutions, got %d", size, len(front))
+	}
+	for k, s := range front {
+		checkSolution(t, objects, capacity, s)
+		if k > 0 && s.Weight < front[k-1].Weight {
+			t.Errorf("Truncated front is not sorted by weight")
+		}
+		for _, other := range front {
+			if other.Weight < s.Weight && other.Values[0] >= s.Values[0] && other.Values[1] >= s.Values[1] {
+				t.Errorf("Solution %+v is dominated by %+v", s, other)
+			}
+		}
+	}
+	// La solution vide, extrémité du front sur le poids, est toujours gardée
+	if front[0].Weight != 0 {
+		t.Errorf("The lightest solution was dropped: %+v", front[0])
+	}
+
+	if _, truncated, _ := multi_objective.ParetoFront(context.Background(), objects, capacity, multi_objective.Options{MaxFront: len(full)}); truncated {
+		t.Errorf("A front that fits must not be truncated")
+	}
+}
+
+func TestParetoFrontErrors(t *testing.T) {
+	objects := []common.Objects{{Weight: 1, Values: []int{1, 2}}, {Weight: 1, Values: []int{1}}}
+	if _, _, err := multi_objective.ParetoFront(context.Background(), objects, 2, multi_objective.Options{}); err == nil {
+		t.Errorf("Expected an error for inconsistent criteria")
+	}
+	if _, _, err := multi_objective.ParetoFront(context.Background(), nil, -1, multi_objective.Options{}); err == nil {
+		t.Errorf("Expected an error for a negative capacity")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := multi_objective.ParetoFront(ctx, objects[:1], 2, multi_objective.Options{}); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	front := []multi_objective.Solution{{Weight: 0, Values: []int{0, 0}, Indices: []int{}}, {Weight: 3, Values: []int{4, 5}, Indices: []int{0, 2}}}
+	var buf bytes.Buffer
+	if err := multi_objective.WriteCSV(&buf, front); err != nil {
+		t.Fatal(err)
+	}
+	expected := "weight,value1,value2,items\n0,0,0,\n3,4,5,0 2\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("Unexpected CSV %q", strings.TrimSpace(got))
+	}
+}
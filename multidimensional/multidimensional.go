@@ -0,0 +1,333 @@
+package multidimensional
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"../common"
+)
+
+/* Options paramètre la séparation et évaluation multidimensionnelle */
+type Options struct {
+	MaxNodes int64 // nombre maximal de noeuds explorés, 0 : pas de limite ; au-delà, la meilleure solution connue est renvoyée
+}
+
+/* surrogate est une relaxation de substitution : les contraintes sont agrégées avec des multiplicateurs */
+type surrogate struct {
+	u        []float64 // multiplicateur de chaque contrainte
+	weights  []float64 // poids agrégé de chaque objet (dans l'ordre de branchement)
+	capacity float64
+	order    []int // positions de branchement triées par rapport valeur/poids agrégé décroissant
+}
+
+/* search contient l'état de la recherche en profondeur */
+type search struct {
+	weights    [][]int // weights[k][d] : consommation de la ressource d par l'objet en position k
+	values     []int
+	capacities []int
+	surrogates []surrogate
+	maxNodes   int64
+	poll       *common.Poll
+
+	used      []int
+	taken     []bool
+	best      int
+	bestTaken []bool
+	nodes     int64
+	aborted   bool
+}
+
+/* Fonction qui vérifie la cohérence des dimensions de l'instance */
+func checkInstance(inst common.MultiInstance) error {
+	d := len(inst.Capacities)
+	if d == 0 {
+		return fmt.Errorf("Multidimensional instance needs at least one capacity")
+	}
+	for k, c := range inst.Capacities {
+		if c < 0 {
+			return fmt.Errorf("Invalid capacity %d for resource %d", c, k)
+		}
+	}
+	for i, obj := range inst.Objects {
+		if len(obj.Weights) != d {
+			return fmt.Errorf("Object %d has %d weights, expected %d", i, len(obj.Weights), d)
+		}
+		for k, w := range obj.Weights {
+			if w < 0 {
+				return fmt.Errorf("Object %d has negative weight %d for resource %d", i, w, k)
+			}
+		}
+	}
+	return nil
+}
+
+// multipliers renvoie les multiplicateurs de substitution utilisés pour les bornes : chaque contrainte seule,
+// puis toutes les contraintes normalisées par leur capacité. Le minimum des bornes obtenues reste une borne.
+func multipliers(capacities []int) [][]float64 {
+	d := len(capacities)
+	var all [][]float64
+	for k := 0; k < d; k++ {
+		u := make([]float64, d)
+		u[k] = 1
+		all = append(all, u)
+	}
+	if d > 1 {
+		u := make([]float64, d)
+		for k, c := range capacities {
+			if c > 0 {
+				u[k] = 1 / float64(c)
+			} else {
+				u[k] = 1
+			}
+		}
+		all = append(all, u)
+	}
+	return all
+}
+
+/* newSurrogate agrège les contraintes avec les multiplicateurs u */
+func newSurrogate(weights [][]int, values []int, capacities []int, u []float64) surrogate {
+	s := surrogate{u: u, weights: make([]float64, len(values))}
+	for k, c := range capacities {
+		s.capacity += u[k] * float64(c)
+	}
+	for i := range values {
+		for k, w := range weights[i] {
+			s.weights[i] += u[k] * float64(w)
+		}
+	}
+
+	s.order = make([]int, len(values))
+	for i := range s.order {
+		s.order[i] = i
+	}
+	sort.SliceStable(s.order, func(a, b int) bool {
+		i, j := s.order[a], s.order[b]
+		return float64(values[i])*s.weights[j] > float64(values[j])*s.weights[i]
+	})
+	return s
+}
+
+/* Greedy construit une solution en ajoutant les objets par valeur décroissante rapportée à leur consommation normalisée */
+func Greedy(inst common.MultiInstance) ([]int, error) {
+	if err := checkInstance(inst); err != nil {
+		return nil, err
+	}
+
+	weights := make([][]int, len(inst.Objects))
+	values := make([]int, len(inst.Objects))
+	for i, obj := range inst.Objects {
+		weights[i] = obj.Weights
+		values[i] = obj.Value
+	}
+	u := multipliers(inst.Capacities)
+	s := newSurrogate(weights, values, inst.Capacities, u[len(u)-1])
+
+	used := make([]int, len(inst.Capacities))
+	indices := make([]int, 0)
+	for _, i := range s.order {
+		if values[i] > 0 && fits(weights[i], used, inst.Capacities) {
+			for k, w := range weights[i] {
+				used[k] += w
+			}
+			indices = append(indices, i)
+		}
+	}
+	return indices, nil
+}
+
+func fits(weights, used, capacities []int) bool {
+	for k, w := range weights {
+		if used[k]+w > capacities[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// BranchAndBound résout le sac à dos multidimensionnel par séparation et évaluation. La solution gloutonne sert
+// de solution initiale ; chaque noeud est évalué par la relaxation linéaire de plusieurs relaxations de
+// substitution. Retourne la valeur, les indices choisis, le nombre de noeuds et si l'optimalité est prouvée
+// (faux si opts.MaxNodes a été atteint ou le contexte annulé, auquel cas la meilleure solution trouvée est renvoyée).
+func BranchAndBound(ctx context.Context, inst common.MultiInstance, opts Options) (int, []int, int64, bool, error) {
+	greedy, err := Greedy(inst)
+	if err != nil {
+		return 0, nil, 0, false, err
+	}
+
+	// Les objets sans valeur n'améliorent jamais la solution ; les autres sont branchés dans l'ordre glouton
+	weights := make([][]int, len(inst.Objects))
+	values := make([]int, len(inst.Objects))
+	for i, obj := range inst.Objects {
+		weights[i] = obj.Weights
+		values[i] = obj.Value
+	}
+	u := multipliers(inst.Capacities)
+	order := make([]int, 0, len(values))
+	for _, i := range newSurrogate(weights, values, inst.Capacities, u[len(u)-1]).order {
+		if values[i] > 0 {
+			order = append(order, i)
+		}
+	}
+	n := len(order)
+
+	s := &search{
+		weights:    make([][]int, n),
+		values:     make([]int, n),
+		capacities: inst.Capacities,
+		maxNodes:   opts.MaxNodes,
+		poll:       common.NewPoll(ctx),
+		used:       make([]int, len(inst.Capacities)),
+		taken:      make([]bool, n),
+		bestTaken:  make([]bool, n),
+	}
+	position := make(map[int]int, n)
+	for k, i := range order {
+		s.weights[k] = weights[i]
+		s.values[k] = values[i]
+		position[i] = k
+	}
+	for _, m := range u {
+		s.surrogates = append(s.surrogates, newSurrogate(s.weights, s.values, inst.Capacities, m))
+	}
+
+	for _, i := range greedy {
+		if k, ok := position[i]; ok {
+			s.bestTaken[k] = true
+			s.best += values[i]
+		}
+	}
+
+	s.branch(0, 0)
+
+	indices := make([]int, 0)
+	for k, taken := range s.bestTaken {
+		if taken {
+			indices = append(indices, order[k])
+		}
+	}
+	return s.best, indices, s.nodes, !s.aborted, nil
+}
+
+/* branch explore le noeud où les k premiers objets sont fixés */
+func (s *search) branch(k, value int) {
+	if s.aborted {
+		return
+	}
+	s.nodes++
+	if (s.maxNodes > 0 && s.nodes > s.maxNodes) || s.poll.Done() {
+		s.aborted = true
+		return
+	}
+
+	if value > s.best {
+		s.best = value
+		copy(s.bestTaken, s.taken)
+	}
+
+	if k == len(s.values) || s.upperBound(k, value) < float64(s.best+1) {
+		return
+	}
+
+	if fits(s.weights[k], s.used, s.capacities) {
+		for d, w := range s.weights[k] {
+			s.used[d] += w
+		}
+		s.taken[k] = true
+		s.branch(k+1, value+s.values[k])
+		s.taken[k] = false
+		for d, w := range s.weights[k] {
+			s.used[d] -= w
+		}
+	}
+
+	s.branch(k+1, value)
+}
+
+/* upperBound renvoie la plus petite des bornes de Dantzig des relaxations de substitution au noeud k */
+func (s *search) upperBound(k, value int) float64 {
+	best := -1.0
+	for _, sur := range s.surrogates {
+		remaining := sur.capacity
+		for d, used := range s.used {
+			remaining -= sur.u[d] * float64(used)
+		}
+
+		bound := float64(value)
+		for _, i := range sur.order {
+			if i < k {
+				continue
+			}
+			if sur.weights[i] <= remaining {
+				remaining -= sur.weights[i]
+				bound += float64(s.values[i])
+				continue
+			}
+			bound += remaining * float64(s.values[i]) / sur.weights[i]
+			break
+		}
+
+		if best < 0 || bound < best {
+			best = bound
+		}
+	}
+
+	// Marge pour les erreurs d'arrondi des calculs en virgule flottante
+	return best + 1e-6
+}
+
+/* Solve résout une instance multidimensionnelle et renvoie le résultat commun à tous les solveurs */
+func Solve(ctx context.Context, inst common.MultiInstance, opts Options) (common.Result, error) {
+	startTime := time.Now()
+	_, indices, nodes, optimal, err := BranchAndBound(ctx, inst, opts)
+	if err != nil {
+		return common.Result{}, err
+	}
+
+	res := common.NewMultiResult(inst, indices, optimal)
+	if !optimal && ctx.Err() != nil {
+		res.Interrupt(0)
+	}
+	res.Stats.Nodes = nodes
+	res.Stats.Duration = time.Since(startTime)
+	return res, nil
+}
+
+/* SolveGreedy applique l'heuristique gloutonne à une instance multidimensionnelle */
+func SolveGreedy(inst common.MultiInstance) (common.Result, error) {
+	startTime := time.Now()
+	indices, err := Greedy(inst)
+	if err != nil {
+		return common.Result{}, err
+	}
+
+	res := common.NewMultiResult(inst, indices, false)
+	res.Stats.Duration = time.Since(startTime)
+	return res, nil
+}
+
+/* Solver adapte la séparation et évaluation multidimensionnelle aux instances à une ressource */
+type Solver struct {
+	Options Options
+}
+
+func init() {
+	common.Register(Solver{})
+}
+
+func (Solver) Name() string {
+	return "multidimensional"
+}
+
+func (s Solver) Solve(ctx context.Context, inst common.Instance) (common.Result, error) {
+	res, err := Solve(ctx, inst.ToMulti(), s.Options)
+	if err != nil {
+		return common.Result{}, err
+	}
+
+	// Ramener le résultat à la forme d'une instance à une seule ressource
+	res.Weights = nil
+	return res, nil
+}
@@ -0,0 +1,136 @@
+package multidimensional_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"../common"
+	"../multidimensional"
+)
+
+func randomInstance(random *rand.Rand, n, dimensions int) common.MultiInstance {
+	inst := common.MultiInstance{Objects: make([]common.MultiObjects, n), Capacities: make([]int, dimensions)}
+	for i := range inst.Objects {
+		weights := make([]int, dimensions)
+		for d := range weights {
+			weights[d] = random.Intn(30)
+			inst.Capacities[d] += weights[d]
+		}
+		inst.Objects[i] = common.MultiObjects{Weights: weights, Value: 1 + random.Intn(50)}
+	}
+	for d := range inst.Capacities {
+		inst.Capacities[d] /= 2
+	}
+	return inst
+}
+
+/* bruteForce énumère tous les sous-ensembles d'objets */
+func bruteForce(inst common.MultiInstance) int {
+	best := 0
+	for mask := 0; mask < 1<<uint(len(inst.Objects)); mask++ {
+		if value, ok := evaluate(inst, mask); ok && value > best {
+			best = value
+		}
+	}
+	return best
+}
+
+func evaluate(inst common.MultiInstance, mask int) (int, bool) {
+	used := make([]int, len(inst.Capacities))
+	value := 0
+	for i, obj := range inst.Objects {
+		if mask&(1<<uint(i)) != 0 {
+			value += obj.Value
+			for d, w := range obj.Weights {
+				used[d] += w
+			}
+		}
+	}
+	for d, c := range inst.Capacities {
+		if used[d] > c {
+			return value, false
+		}
+	}
+	return value, true
+}
+
+/* check vérifie qu'une solution respecte toutes les capacités et renvoie sa valeur */
+func check(t *testing.T, inst common.MultiInstance, indices []int) int {
+	t.Helper()
+	mask := 0
+	for _, i := range indices {
+		if i < 0 || i >= len(inst.Objects) || mask&(1<<uint(i)) != 0 {
+			t.Fatalf("Invalid or repeated index %d in %v", i, indices)
+		}
+		mask |= 1 << uint(i)
+	}
+	value, ok := evaluate(inst, mask)
+	if !ok {
+		t.Errorf("Solution %v exceeds the capacities %v", indices, inst.Capacities)
+	}
+	return value
+}
+
+func TestAgainstBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 60; trial++ {
+		inst := randomInstance(random, random.Intn(13), 1+trial%4)
+		value, indices, _, optimal, err := multidimensional.BranchAndBound(context.Background(), inst, multidimensional.Options{})
+		if err != nil || !optimal {
+			t.Fatalf("Trial %d: optimal %t, %v", trial, optimal, err)
+		}
+		if got := check(t, inst, indices); got != value {
+			t.Errorf("Trial %d: indices give value %d, announced %d", trial, got, value)
+		}
+		optimum := bruteForce(inst)
+		if value != optimum {
+			t.Errorf("Trial %d: value %d, optimum %d", trial, value, optimum)
+		}
+
+		greedy, err := multidimensional.Greedy(inst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := check(t, inst, greedy); got > optimum {
+			t.Errorf("Trial %d: greedy value %d above optimum %d", trial, got, optimum)
+		}
+	}
+}
+
+func TestNodeLimit(t *testing.T) {
+	inst := randomInstance(rand.New(rand.NewSource(2)), 40, 3)
+	res, err := multidimensional.Solve(context.Background(), inst, multidimensional.Options{MaxNodes: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(t, inst, res.Indices)
+	if res.Optimal || res.Stats.Interrupted {
+		t.Errorf("Node limit: expected a non-optimal, non-interrupted result, got %+v", res.Stats)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if res, err = multidimensional.Solve(ctx, inst, multidimensional.Options{}); err != nil {
+		t.Fatal(err)
+	}
+	check(t, inst, res.Indices)
+	if res.Optimal || !res.Stats.Interrupted {
+		t.Errorf("Cancelled context: expected an interrupted result, got %+v", res.Stats)
+	}
+}
+
+func TestInvalidInstances(t *testing.T) {
+	instances := map[string]common.MultiInstance{
+		"missing weight":    {Objects: []common.MultiObjects{{Weights: []int{1}, Value: 1}}, Capacities: []int{2, 2}},
+		"negative capacity": {Objects: []common.MultiObjects{{Weights: []int{1}, Value: 1}}, Capacities: []int{-1}},
+	}
+	for name, inst := range instances {
+		if _, _, _, _, err := multidimensional.BranchAndBound(context.Background(), inst, multidimensional.Options{}); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+		if _, err := multidimensional.Greedy(inst); err == nil {
+			t.Errorf("%s: greedy, expected an error", name)
+		}
+	}
+}
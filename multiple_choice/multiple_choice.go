@@ -0,0 +1,283 @@
+package multiple_choice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"time"
+
+	"../common"
+)
+
+var ErrInfeasible = errors.New("No selection of one object per class fits in the knapsack")
+
+const minusInfinity = -int(^uint(0)>>1) - 1
+
+/* class regroupe les objets d'une même classe, triés par poids croissant */
+type class struct {
+	id    int
+	items []int
+}
+
+// Knapsack résout le sac à dos à choix multiples : exactement un objet de chaque classe (champ Class) doit être
+// choisi. Les objets dominés (plus lourds et pas plus précieux qu'un autre de la même classe) sont d'abord
+// éliminés, puis une programmation dynamique sur les classes calcule la meilleure valeur pour chaque capacité.
+// Retourne la valeur optimale, les indices choisis (un par classe), la borne de la relaxation linéaire
+// (calculée sur les enveloppes convexes des classes), le nombre de cases calculées et si le contexte a
+// interrompu la programmation dynamique, auquel cas la solution gloutonne (un objet par classe, réalisable) est
+// renvoyée à la place de l'optimum.
+func Knapsack(ctx context.Context, objects []common.Objects, capacity int) (int, []int, int, int64, bool, error) {
+	if capacity < 0 {
+		return 0, nil, 0, 0, false, fmt.Errorf("Invalid capacity %d", capacity)
+	}
+
+	classes := groupByClass(objects)
+	lightest := 0
+	for k := range classes {
+		classes[k].items = removeDominated(objects, classes[k].items)
+		if len(classes[k].items) == 0 {
+			return 0, nil, 0, 0, false, ErrInfeasible
+		}
+		lightest += objects[classes[k].items[0]].Weight
+	}
+	// L'objet le plus léger de chaque classe est la sélection la plus légère possible
+	if lightest > capacity {
+		return 0, nil, 0, 0, false, ErrInfeasible
+	}
+
+	// best[c] : meilleure valeur avec un objet de chaque classe traitée et un poids au plus c
+	best := make([]int, capacity+1)
+	choice := make([][]int32, len(classes))
+	var cells int64
+	poll := common.NewPoll(ctx)
+
+	for k, cl := range classes {
+		next := make([]int, capacity+1)
+		choice[k] = make([]int32, capacity+1)
+		for c := 0; c <= capacity; c++ {
+			if poll.Done() {
+				value, indices := greedy(objects, classes, capacity)
+				return value, indices, linearBound(objects, classes, capacity), cells, true, nil
+			}
+			next[c] = minusInfinity
+			choice[k][c] = -1
+			for pos, i := range cl.items {
+				w := objects[i].Weight
+				if w > c {
+					break
+				}
+				if best[c-w] != minusInfinity && best[c-w]+objects[i].Value > next[c] {
+					next[c] = best[c-w] + objects[i].Value
+					choice[k][c] = int32(pos)
+				}
+			}
+			cells += int64(len(cl.items))
+		}
+		best = next
+	}
+
+	if best[capacity] == minusInfinity {
+		return 0, nil, 0, cells, false, ErrInfeasible
+	}
+
+	// Récupérer l'objet choisi dans chaque classe, de la dernière à la première
+	indices := make([]int, len(classes))
+	c := capacity
+	for k := len(classes) - 1; k >= 0; k-- {
+		i := classes[k].items[choice[k][c]]
+		indices[k] = i
+		c -= objects[i].Weight
+	}
+
+	return best[capacity], indices, linearBound(objects, classes, capacity), cells, false, nil
+}
+
+/* groupByClass regroupe les indices des objets par classe, les classes étant triées par identifiant */
+func groupByClass(objects []common.Objects) []class {
+	byID := make(map[int]int)
+	var classes []class
+	for i, obj := range objects {
+		k, ok := byID[obj.Class]
+		if !ok {
+			k = len(classes)
+			byID[obj.Class] = k
+			classes = append(classes, class{id: obj.Class})
+		}
+		classes[k].items = append(classes[k].items, i)
+	}
+
+	sort.Slice(classes, func(a, b int) bool { return classes[a].id < classes[b].id })
+	return classes
+}
+
+/* removeDominated trie les objets d'une classe par poids croissant et ne garde que ceux qui apportent plus de valeur */
+func removeDominated(objects []common.Objects, items []int) []int {
+	sorted := append([]int(nil), items...)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		i, j := sorted[a], sorted[b]
+		if objects[i].Weight != objects[j].Weight {
+			return objects[i].Weight < objects[j].Weight
+		}
+		return objects[i].Value > objects[j].Value
+	})
+
+	kept := make([]int, 0, len(sorted))
+	for _, i := range sorted {
+		if len(kept) == 0 || objects[i].Value > objects[kept[len(kept)-1]].Value {
+			kept = append(kept, i)
+		}
+	}
+	return kept
+}
+
+// lpHull ne garde que les objets non LP-dominés d'une classe (déjà triée et sans objet dominé) : ce sont les
+// sommets de l'enveloppe convexe supérieure dans le plan (poids, valeur), à pentes décroissantes.
+func lpHull(objects []common.Objects, items []int) []int {
+	hull := make([]int, 0, len(items))
+	for _, i := range items {
+		for len(hull) >= 2 {
+			a, b := objects[hull[len(hull)-2]], objects[hull[len(hull)-1]]
+			// b est LP-dominé si la pente de a vers i est au moins celle de a vers b
+			if (objects[i].Value-a.Value)*(b.Weight-a.Weight) >= (b.Value-a.Value)*(objects[i].Weight-a.Weight) {
+				hull = hull[:len(hull)-1]
+			} else {
+				break
+			}
+		}
+		hull = append(hull, i)
+	}
+	return hull
+}
+
+// linearBound calcule la borne de la relaxation linéaire (Sinha-Zoltners) : on part de l'objet le plus léger de
+// chaque classe, puis on applique les améliorations des enveloppes convexes par pente décroissante, la dernière
+// en partie seulement.
+func linearBound(objects []common.Objects, classes []class, capacity int) int {
+	type step struct{ weight, value int }
+	var steps []step
+	value, remaining := 0, capacity
+
+	for _, cl := range classes {
+		hull := lpHull(objects, cl.items)
+		value += objects[hull[0]].Value
+		remaining -= objects[hull[0]].Weight
+		for h := 1; h < len(hull); h++ {
+			a, b := objects[hull[h-1]], objects[hull[h]]
+			steps = append(steps, step{b.Weight - a.Weight, b.Value - a.Value})
+		}
+	}
+
+	sort.SliceStable(steps, func(a, b int) bool {
+		return steps[a].value*steps[b].weight > steps[b].value*steps[a].weight
+	})
+	for _, s := range steps {
+		if s.weight > remaining {
+			return value + remaining*s.value/s.weight
+		}
+		remaining -= s.weight
+		value += s.value
+	}
+	return value
+}
+
+// greedy construit une sélection réalisable d'un objet par classe : l'objet le plus léger de chaque classe, puis
+// les améliorations des enveloppes convexes par pente décroissante tant qu'elles tiennent dans le sac, une classe
+// s'arrêtant à sa première amélioration refusée. La capacité doit suffire pour les objets les plus légers.
+func greedy(objects []common.Objects, classes []class, capacity int) (int, []int) {
+	type step struct{ class, pos, weight, value int }
+	var steps []step
+	hulls := make([][]int, len(classes))
+	chosen := make([]int, len(classes))
+	remaining := capacity
+
+	for k, cl := range classes {
+		hulls[k] = lpHull(objects, cl.items)
+		remaining -= objects[hulls[k][0]].Weight
+		for h := 1; h < len(hulls[k]); h++ {
+			a, b := objects[hulls[k][h-1]], objects[hulls[k][h]]
+			steps = append(steps, step{k, h, b.Weight - a.Weight, b.Value - a.Value})
+		}
+	}
+
+	// Les pentes d'une enveloppe sont décroissantes : le tri stable garde l'ordre des étapes de chaque classe
+	sort.SliceStable(steps, func(a, b int) bool {
+		return steps[a].value*steps[b].weight > steps[b].value*steps[a].weight
+	})
+	blocked := make([]bool, len(classes))
+	for _, s := range steps {
+		if blocked[s.class] {
+			continue
+		}
+		if s.weight > remaining {
+			blocked[s.class] = true
+			continue
+		}
+		remaining -= s.weight
+		chosen[s.class] = s.pos
+	}
+
+	value := 0
+	indices := make([]int, len(classes))
+	for k := range classes {
+		indices[k] = hulls[k][chosen[k]]
+		value += objects[indices[k]].Value
+	}
+	return value, indices
+}
+
+// LoadJSONData lit un fichier d'objets à choix multiples. Deux formes sont acceptées : un tableau d'objets
+// portant chacun un champ "class", ou un tableau de classes, chacune étant un tableau d'objets.
+func LoadJSONData(filename string) ([]common.Objects, error) {
+	fileBytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []common.Objects
+	if err = json.Unmarshal(fileBytes, &objects); err == nil {
+		return objects, nil
+	}
+
+	var groups [][]common.Objects
+	if err := json.Unmarshal(fileBytes, &groups); err != nil {
+		return nil, err
+	}
+	objects = nil
+	for k, group := range groups {
+		for _, obj := range group {
+			obj.Class = k
+			objects = append(objects, obj)
+		}
+	}
+	return objects, nil
+}
+
+/* Solver adapte le sac à dos à choix multiples à l'interface common.Solver */
+type Solver struct{}
+
+func init() {
+	common.RegisterVariant(Solver{})
+}
+
+func (Solver) Name() string {
+	return "multiple_choice"
+}
+
+func (Solver) Solve(ctx context.Context, inst common.Instance) (common.Result, error) {
+	startTime := time.Now()
+	_, indices, bound, cells, interrupted, err := Knapsack(ctx, inst.Objects, inst.Capacity)
+	if err != nil {
+		return common.Result{}, err
+	}
+
+	res := common.NewResult(inst, indices, true)
+	if interrupted {
+		res.Interrupt(bound)
+	}
+	res.Stats.Nodes = cells
+	res.Stats.Duration = time.Since(startTime)
+	return res, nil
+}
@@ -0,0 +1,115 @@
+package multiple_choice_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"../common"
+	"../multiple_choice"
+)
+
+/* instance tire classes classes de 1 à 4 objets */
+func instance(random *rand.Rand, classes int) common.Instance {
+	var objects []common.Objects
+	minimum := 0
+	for k := 0; k < classes; k++ {
+		lightest := 1 << 30
+		for j := 0; j < 1+random.Intn(4); j++ {
+			obj := common.Objects{Weight: 1 + random.Intn(30), Value: 1 + random.Intn(50), Class: k}
+			if obj.Weight < lightest {
+				lightest = obj.Weight
+			}
+			objects = append(objects, obj)
+		}
+		minimum += lightest
+	}
+	return common.Instance{Objects: objects, Capacity: minimum + random.Intn(40)}
+}
+
+/* bruteForce énumère toutes les sélections d'un objet par classe */
+func bruteForce(inst common.Instance, classes int) int {
+	byClass := make([][]int, classes)
+	for i, obj := range inst.Objects {
+		byClass[obj.Class] = append(byClass[obj.Class], i)
+	}
+
+	best := -1
+	var walk func(k, weight, value int)
+	walk = func(k, weight, value int) {
+		if weight > inst.Capacity {
+			return
+		}
+		if k == classes {
+			if value > best {
+				best = value
+			}
+			return
+		}
+		for _, i := range byClass[k] {
+			walk(k+1, weight+inst.Objects[i].Weight, value+inst.Objects[i].Value)
+		}
+	}
+	walk(0, 0, 0)
+	return best
+}
+
+/* checkFeasible vérifie qu'un résultat choisit exactement un objet par classe et tient dans le sac */
+func checkFeasible(t *testing.T, inst common.Instance, classes int, res common.Result) {
+	t.Helper()
+	seen := make(map[int]bool)
+	for _, i := range res.Indices {
+		if seen[inst.Objects[i].Class] {
+			t.Errorf("Class %d chosen twice", inst.Objects[i].Class)
+		}
+		seen[inst.Objects[i].Class] = true
+	}
+	if len(seen) != classes || res.Weight > inst.Capacity {
+		t.Errorf("Infeasible selection %v (weight %d, capacity %d)", res.Indices, res.Weight, inst.Capacity)
+	}
+}
+
+func TestSolverAgainstBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 200; trial++ {
+		classes := 1 + random.Intn(5)
+		inst := instance(random, classes)
+		res, err := multiple_choice.Solver{}.Solve(context.Background(), inst)
+		if err != nil {
+			t.Fatalf("Trial %d: %v", trial, err)
+		}
+		checkFeasible(t, inst, classes, res)
+		if optimum := bruteForce(inst, classes); res.Value != optimum || res.Bound < optimum {
+			t.Errorf("Trial %d: value %d bound %d, optimum %d", trial, res.Value, res.Bound, optimum)
+		}
+	}
+}
+
+func TestInterruptedSolverIsFeasible(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	random := rand.New(rand.NewSource(2))
+	for trial := 0; trial < 50; trial++ {
+		classes := 1 + random.Intn(5)
+		inst := instance(random, classes)
+		res, err := multiple_choice.Solver{}.Solve(ctx, inst)
+		if err != nil {
+			t.Fatalf("Trial %d: %v", trial, err)
+		}
+		if !res.Stats.Interrupted || res.Optimal {
+			t.Errorf("Trial %d: expected an interrupted result, got %+v", trial, res)
+		}
+		checkFeasible(t, inst, classes, res)
+		if optimum := bruteForce(inst, classes); res.Value > optimum || res.Bound < optimum {
+			t.Errorf("Trial %d: value %d bound %d, optimum %d", trial, res.Value, res.Bound, optimum)
+		}
+	}
+}
+
+func TestInfeasible(t *testing.T) {
+	inst := common.Instance{Objects: []common.Objects{{Weight: 4, Value: 1, Class: 0}, {Weight: 5, Value: 1, Class: 1}}, Capacity: 8}
+	if _, err := (multiple_choice.Solver{}).Solve(context.Background(), inst); err != multiple_choice.ErrInfeasible {
+		t.Errorf("Expected ErrInfeasible, got %v", err)
+	}
+}
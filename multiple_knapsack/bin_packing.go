@@ -0,0 +1,232 @@
+package multiple_knapsack
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"../algo_prog_dynamique"
+	"../common"
+)
+
+/* packing contient l'état de la recherche en profondeur du rangement en boîtes */
+type packing struct {
+	weights  []int // poids des objets, par ordre décroissant
+	capacity int
+	maxNodes int64
+	poll     *common.Poll
+
+	bins     []int // charge de chaque boîte ouverte
+	assign   []int
+	best     int
+	bestBins []int
+	nodes    int64
+	aborted  bool
+}
+
+// BinPacking range tous les objets dans le plus petit nombre possible de boîtes de capacité capacity.
+// La solution initiale remplit les boîtes une à une en maximisant leur charge avec la programmation dynamique
+// du sac à dos (valeur = poids), et est comparée au rangement First Fit Decreasing. Si elle n'atteint pas la
+// borne inférieure, une recherche arborescente prouve l'optimalité (dans la limite de opts.MaxNodes).
+// Dans le résultat, Value contient le nombre de boîtes utilisées et Bound sa borne inférieure. Si le contexte
+// est annulé, le meilleur rangement trouvé est renvoyé avec Stats.Interrupted.
+func BinPacking(ctx context.Context, objects []common.Objects, capacity int, opts Options) (Result, error) {
+	startTime := time.Now()
+	total := 0
+	for i, obj := range objects {
+		if obj.Weight < 0 || obj.Weight > capacity {
+			return Result{}, fmt.Errorf("Object %d of weight %d does not fit in a bin of capacity %d", i, obj.Weight, capacity)
+		}
+		total += obj.Weight
+	}
+
+	order := make([]int, len(objects))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return objects[order[a]].Weight > objects[order[b]].Weight })
+
+	p := &packing{capacity: capacity, maxNodes: opts.MaxNodes, poll: common.NewPoll(ctx), weights: make([]int, len(order))}
+	for k, i := range order {
+		p.weights[k] = objects[i].Weight
+	}
+
+	lower := lowerBound(p.weights, capacity, total)
+
+	p.bestBins = fillBins(p.weights, capacity)
+	if ffd := firstFitDecreasing(p.weights, capacity); count(ffd) < count(p.bestBins) {
+		p.bestBins = ffd
+	}
+	p.best = count(p.bestBins)
+
+	if p.best > lower {
+		p.assign = make([]int, len(p.weights))
+		p.branch(0, total, lower)
+	}
+
+	res := Result{Bags: make([][]int, p.best)}
+	for k, b := range p.bestBins {
+		res.Bags[b] = append(res.Bags[b], order[k])
+	}
+	for b := range res.Bags {
+		sort.Ints(res.Bags[b])
+	}
+
+	res.Indices = make([]int, len(objects))
+	for i := range res.Indices {
+		res.Indices[i] = i
+	}
+	res.Weight = total
+	res.Value = p.best
+	res.Optimal = !p.aborted
+	res.Bound = lower
+	if res.Optimal {
+		// La recherche a prouvé qu'aucun rangement n'utilise moins de boîtes
+		res.Bound = res.Value
+	}
+	res.Stats.Interrupted = p.poll.Stopped()
+	res.Stats.Nodes = p.nodes
+	res.Stats.Duration = time.Since(startTime)
+	return res, nil
+}
+
+/* lowerBound renvoie max(ceil(somme des poids / capacité), nombre d'objets de poids supérieur à la moitié) */
+func lowerBound(weights []int, capacity, total int) int {
+	if len(weights) == 0 {
+		return 0
+	}
+	if capacity == 0 {
+		return 1
+	}
+
+	lower := (total + capacity - 1) / capacity
+	large := 0
+	for _, w := range weights {
+		if 2*w > capacity {
+			large++
+		}
+	}
+	if large > lower {
+		lower = large
+	}
+	if lower == 0 {
+		lower = 1
+	}
+	return lower
+}
+
+/* count renvoie le nombre de boîtes utilisées par une affectation */
+func count(assign []int) int {
+	bins := 0
+	for _, b := range assign {
+		if b+1 > bins {
+			bins = b + 1
+		}
+	}
+	return bins
+}
+
+/* fillBins remplit les boîtes une à une en résolvant un sac à dos où la valeur de chaque objet est son poids */
+func fillBins(weights []int, capacity int) []int {
+	assign := make([]int, len(weights))
+	for k := range assign {
+		assign[k] = -1
+	}
+
+	for bin, left := 0, len(weights); left > 0; bin++ {
+		var free []int
+		var items []common.Objects
+		for k, b := range assign {
+			if b < 0 {
+				free = append(free, k)
+				// Le +1 fait aussi ranger les objets de poids nul
+				items = append(items, common.Objects{Weight: weights[k], Value: weights[k]*(len(weights)+1) + 1})
+			}
+		}
+
+		_, chosen, _ := algo_prog_dynamique.KnapsackLowMemory(items, capacity)
+		for _, c := range chosen {
+			assign[free[c]] = bin
+		}
+		left -= len(chosen)
+	}
+
+	return assign
+}
+
+/* firstFitDecreasing range chaque objet (par poids décroissant) dans la première boîte où il rentre */
+func firstFitDecreasing(weights []int, capacity int) []int {
+	assign := make([]int, len(weights))
+	var loads []int
+	for k, w := range weights {
+		assign[k] = -1
+		for b, load := range loads {
+			if load+w <= capacity {
+				loads[b] += w
+				assign[k] = b
+				break
+			}
+		}
+		if assign[k] < 0 {
+			loads = append(loads, w)
+			assign[k] = len(loads) - 1
+		}
+	}
+	return assign
+}
+
+/* branch range l'objet k dans une boîte ouverte ou dans une nouvelle boîte ; remaining est le poids restant à ranger */
+func (p *packing) branch(k, remaining, lower int) {
+	if p.aborted || p.best == lower {
+		return
+	}
+	p.nodes++
+	if (p.maxNodes > 0 && p.nodes > p.maxNodes) || p.poll.Done() {
+		p.aborted = true
+		return
+	}
+
+	if k == len(p.weights) {
+		if len(p.bins) < p.best {
+			p.best = len(p.bins)
+			p.bestBins = append([]int(nil), p.assign...)
+		}
+		return
+	}
+
+	// Borne : la place libre des boîtes ouvertes ne suffit pas, il faut ouvrir de nouvelles boîtes
+	free := 0
+	for _, load := range p.bins {
+		free += p.capacity - load
+	}
+	needed := len(p.bins)
+	if remaining > free {
+		needed += (remaining - free + p.capacity - 1) / p.capacity
+	}
+	if needed >= p.best {
+		return
+	}
+
+	w := p.weights[k]
+	tried := make(map[int]bool, len(p.bins))
+	for b, load := range p.bins {
+		// Deux boîtes de même charge sont interchangeables
+		if load+w > p.capacity || tried[load] {
+			continue
+		}
+		tried[load] = true
+
+		p.bins[b] += w
+		p.assign[k] = b
+		p.branch(k+1, remaining-w, lower)
+		p.bins[b] -= w
+	}
+
+	if len(p.bins)+1 < p.best {
+		p.bins = append(p.bins, w)
+		p.assign[k] = len(p.bins) - 1
+		p.branch(k+1, remaining-w, lower)
+		p.bins = p.bins[:len(p.bins)-1]
+	}
+}
@@ -0,0 +1,221 @@
+package multiple_knapsack
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"../algo_prog_dynamique"
+	"../common"
+)
+
+/* Options paramètre les recherches arborescentes du paquet */
+type Options struct {
+	MaxNodes int64 // nombre maximal de noeuds explorés, 0 : pas de limite ; au-delà, la meilleure solution connue est renvoyée
+}
+
+/* Result étend le résultat commun avec le contenu de chaque sac */
+type Result struct {
+	common.Result
+	Bags [][]int // indices des objets rangés dans chaque sac (ou boîte)
+}
+
+/* search contient l'état de la recherche en profondeur du sac à dos multiple */
+type search struct {
+	objects  []common.Objects
+	order    []int // objets de valeur positive, par rapport valeur/poids décroissant
+	residual []int // capacité restante de chaque sac
+	maxNodes int64
+	poll     *common.Poll
+
+	assign     []int // sac de l'objet en position k, -1 s'il n'est pas rangé
+	best       int
+	bestAssign []int
+	nodes      int64
+	aborted    bool
+}
+
+// Knapsack résout le problème du sac à dos multiple (remplir plusieurs sacs de capacités différentes avec un
+// même ensemble d'objets) par une méthode « bound-and-bound » dans l'esprit de MTM (Martello-Toth) :
+//   - borne supérieure : relaxation de substitution, c'est-à-dire un seul sac dont la capacité est la somme des
+//     capacités restantes, résolu exactement par la programmation dynamique de algo_prog_dynamique ;
+//   - borne inférieure : à chaque noeud, les sacs sont complétés l'un après l'autre par la même programmation
+//     dynamique avec les objets restants, ce qui améliore la meilleure solution connue ;
+//   - un noeud dont les deux bornes coïncident est résolu sans exploration, en particulier la racine.
+//
+// Si le contexte est annulé ou opts.MaxNodes atteint, la meilleure affectation trouvée est renvoyée, avec la
+// borne de substitution de la racine pour Bound.
+func Knapsack(ctx context.Context, objects []common.Objects, capacities []int, opts Options) (Result, error) {
+	startTime := time.Now()
+	for j, c := range capacities {
+		if c < 0 {
+			return Result{}, fmt.Errorf("Invalid capacity %d for bag %d", c, j)
+		}
+	}
+
+	s := &search{
+		objects:  objects,
+		residual: append([]int(nil), capacities...),
+		maxNodes: opts.MaxNodes,
+		poll:     common.NewPoll(ctx),
+	}
+	for _, i := range common.RatioOrder(objects) {
+		if objects[i].Value > 0 {
+			s.order = append(s.order, i)
+		}
+	}
+	s.assign = make([]int, len(s.order))
+	for k := range s.assign {
+		s.assign[k] = -1
+	}
+
+	// Borne inférieure initiale : remplissage successif des sacs
+	s.bestAssign, s.best = s.fill(0)
+	root := s.upperBound(0, 0)
+
+	s.branch(0, 0)
+
+	res := Result{Bags: make([][]int, len(capacities))}
+	indices := make([]int, 0)
+	for k, j := range s.bestAssign {
+		if j >= 0 {
+			res.Bags[j] = append(res.Bags[j], s.order[k])
+			indices = append(indices, s.order[k])
+		}
+	}
+	for j := range res.Bags {
+		sort.Ints(res.Bags[j])
+	}
+
+	res.Result = common.NewResult(common.Instance{Objects: objects}, indices, !s.aborted)
+	if s.aborted {
+		res.Bound = root
+		if s.poll.Stopped() {
+			res.Interrupt(root)
+		}
+	}
+	res.Stats.Nodes = s.nodes
+	res.Stats.Duration = time.Since(startTime)
+	return res, nil
+}
+
+// fill complète les sacs, par capacité restante croissante, chacun de façon optimale avec les objets libres à
+// partir de la position k ; renvoie l'affectation de tous les objets (celle de s.assign avant k) et la valeur
+// ajoutée.
+func (s *search) fill(k int) ([]int, int) {
+	assign := append([]int(nil), s.assign...)
+
+	bags := make([]int, len(s.residual))
+	for j := range bags {
+		bags[j] = j
+	}
+	sort.SliceStable(bags, func(a, b int) bool { return s.residual[bags[a]] < s.residual[bags[b]] })
+
+	value := 0
+	for _, j := range bags {
+		var free []int
+		var items []common.Objects
+		for q := k; q < len(s.order); q++ {
+			if assign[q] < 0 {
+				free = append(free, q)
+				items = append(items, s.objects[s.order[q]])
+			}
+		}
+
+		v, chosen, _ := algo_prog_dynamique.KnapsackLowMemory(items, s.residual[j])
+		for _, c := range chosen {
+			assign[free[c]] = j
+		}
+		value += v
+	}
+
+	return assign, value
+}
+
+/* branch explore le noeud où les k premiers objets (dans l'ordre du rapport) sont rangés ou écartés */
+func (s *search) branch(k, value int) {
+	if s.aborted {
+		return
+	}
+	s.nodes++
+	if (s.maxNodes > 0 && s.nodes > s.maxNodes) || s.poll.Done() {
+		s.aborted = true
+		return
+	}
+
+	if value > s.best {
+		s.best = value
+		copy(s.bestAssign, s.assign)
+	}
+
+	if k == len(s.order) {
+		return
+	}
+	upper := s.upperBound(k, value)
+	if upper <= s.best {
+		return
+	}
+
+	// Bound-and-bound : la complétion gloutonne du noeud peut atteindre sa borne, qui est alors résolu
+	if completion, extra := s.fill(k); value+extra > s.best {
+		s.best = value + extra
+		s.bestAssign = completion
+	}
+	if upper <= s.best {
+		return
+	}
+
+	obj := s.objects[s.order[k]]
+	tried := make(map[int]bool, len(s.residual))
+	for j, r := range s.residual {
+		// Deux sacs de même capacité restante sont interchangeables
+		if obj.Weight > r || tried[r] {
+			continue
+		}
+		tried[r] = true
+
+		s.residual[j] -= obj.Weight
+		s.assign[k] = j
+		s.branch(k+1, value+obj.Value)
+		s.assign[k] = -1
+		s.residual[j] += obj.Weight
+	}
+
+	s.branch(k+1, value)
+}
+
+/* upperBound calcule la borne de la relaxation de substitution sur les objets restants */
+func (s *search) upperBound(k, value int) int {
+	total, largest := 0, 0
+	for _, r := range s.residual {
+		total += r
+		if r > largest {
+			largest = r
+		}
+	}
+
+	// Seuls les objets qui rentrent dans au moins un sac sont utiles
+	var items []common.Objects
+	for _, i := range s.order[k:] {
+		if s.objects[i].Weight <= largest {
+			items = append(items, s.objects[i])
+		}
+	}
+
+	// La borne de Dantzig est calculée d'abord ; la programmation dynamique n'est lancée que si elle ne suffit pas
+	remaining, dantzig := total, value
+	for _, obj := range items {
+		if obj.Weight > remaining {
+			dantzig += remaining * obj.Value / obj.Weight
+			break
+		}
+		remaining -= obj.Weight
+		dantzig += obj.Value
+	}
+	if dantzig <= s.best {
+		return dantzig
+	}
+
+	return value + algo_prog_dynamique.KnapsackValue(items, total)
+}
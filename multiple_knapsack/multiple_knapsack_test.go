@@ -0,0 +1,182 @@
+package multiple_knapsack_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"../common"
+	"../multiple_knapsack"
+)
+
+func randomObjects(random *rand.Rand, n, maxWeight int) []common.Objects {
+	objects := make([]common.Objects, n)
+	for i := range objects {
+		objects[i] = common.Objects{Weight: 1 + random.Intn(maxWeight), Value: 1 + random.Intn(50)}
+	}
+	return objects
+}
+
+/* bruteForceMKP essaie toutes les affectations de chaque objet à un sac ou à aucun */
+func bruteForceMKP(objects []common.Objects, capacities []int) int {
+	loads := make([]int, len(capacities))
+	best := 0
+	var walk func(i, value int)
+	walk = func(i, value int) {
+		if i == len(objects) {
+			if value > best {
+				best = value
+			}
+			return
+		}
+		walk(i+1, value)
+		for j := range loads {
+			if loads[j]+objects[i].Weight <= capacities[j] {
+				loads[j] += objects[i].Weight
+				walk(i+1, value+objects[i].Value)
+				loads[j] -= objects[i].Weight
+			}
+		}
+	}
+	walk(0, 0)
+	return best
+}
+
+/* bruteForceBins renvoie le plus petit nombre de boîtes qui contiennent tous les objets */
+func bruteForceBins(objects []common.Objects, capacity int) int {
+	var fits func(i int, loads []int) bool
+	fits = func(i int, loads []int) bool {
+		if i == len(objects) {
+			return true
+		}
+		for b := range loads {
+			if loads[b]+objects[i].Weight <= capacity {
+				loads[b] += objects[i].Weight
+				ok := fits(i+1, loads)
+				loads[b] -= objects[i].Weight
+				if ok {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	for bins := 0; ; bins++ {
+		if fits(0, make([]int, bins)) {
+			return bins
+		}
+	}
+}
+
+// checkBags vérifie que chaque objet est rangé au plus une fois (exactement une fois si all), que chaque sac
+// respecte sa capacité et que le résultat correspond au contenu des sacs.
+func checkBags(t *testing.T, objects []common.Objects, capacity func(j int) int, res multiple_knapsack.Result, all bool) {
+	t.Helper()
+	seen := make(map[int]bool)
+	value := 0
+	for j, bag := range res.Bags {
+		load := 0
+		for _, i := range bag {
+			if seen[i] {
+				t.Errorf("Object %d packed twice", i)
+			}
+			seen[i] = true
+			load += objects[i].Weight
+			value += objects[i].Value
+		}
+		if load > capacity(j) {
+			t.Errorf("Bag %d holds %d, capacity %d", j, load, capacity(j))
+		}
+	}
+	if all && len(seen) != len(objects) {
+		t.Errorf("Only %d of %d objects packed", len(seen), len(objects))
+	}
+	if !all && (value != res.Value || len(seen) != len(res.Indices)) {
+		t.Errorf("Bags hold value %d and %d objects, result reports %d and %v", value, len(seen), res.Value, res.Indices)
+	}
+}
+
+func TestKnapsackAgainstBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 100; trial++ {
+		objects := randomObjects(random, 1+random.Intn(8), 30)
+		capacities := make([]int, 1+random.Intn(3))
+		for j := range capacities {
+			capacities[j] = random.Intn(50)
+		}
+
+		res, err := multiple_knapsack.Knapsack(context.Background(), objects, capacities, multiple_knapsack.Options{})
+		if err != nil {
+			t.Fatalf("Trial %d: %v", trial, err)
+		}
+		checkBags(t, objects, func(j int) int { return capacities[j] }, res, false)
+		if optimum := bruteForceMKP(objects, capacities); !res.Optimal || res.Value != optimum {
+			t.Errorf("Trial %d: value %d (optimal: %t), optimum %d", trial, res.Value, res.Optimal, optimum)
+		}
+	}
+}
+
+func TestKnapsackNodeLimit(t *testing.T) {
+	random := rand.New(rand.NewSource(2))
+	objects := randomObjects(random, 30, 40)
+	capacities := []int{45, 60, 75}
+
+	res, err := multiple_knapsack.Knapsack(context.Background(), objects, capacities, multiple_knapsack.Options{MaxNodes: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBags(t, objects, func(j int) int { return capacities[j] }, res, false)
+	if res.Optimal && res.Stats.Nodes > 1 {
+		t.Errorf("Node limit ignored: %+v", res.Result)
+	}
+	if !res.Optimal && res.Bound < res.Value {
+		t.Errorf("Bound %d below value %d", res.Bound, res.Value)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if res, err = multiple_knapsack.Knapsack(ctx, objects, capacities, multiple_knapsack.Options{}); err != nil {
+		t.Fatal(err)
+	}
+	checkBags(t, objects, func(j int) int { return capacities[j] }, res, false)
+}
+
+func TestBinPackingAgainstBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(3))
+	for trial := 0; trial < 100; trial++ {
+		capacity := 10 + random.Intn(40)
+		objects := randomObjects(random, random.Intn(9), capacity)
+
+		res, err := multiple_knapsack.BinPacking(context.Background(), objects, capacity, multiple_knapsack.Options{})
+		if err != nil {
+			t.Fatalf("Trial %d: %v", trial, err)
+		}
+		checkBags(t, objects, func(int) int { return capacity }, res, true)
+		if optimum := bruteForceBins(objects, capacity); !res.Optimal || res.Value != optimum || len(res.Bags) != optimum || res.Bound != optimum {
+			t.Errorf("Trial %d: %d bins (optimal: %t, bound %d), optimum %d", trial, res.Value, res.Optimal, res.Bound, optimum)
+		}
+	}
+}
+
+func TestBinPackingNodeLimit(t *testing.T) {
+	random := rand.New(rand.NewSource(4))
+	objects := randomObjects(random, 40, 60)
+	res, err := multiple_knapsack.BinPacking(context.Background(), objects, 100, multiple_knapsack.Options{MaxNodes: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBags(t, objects, func(int) int { return 100 }, res, true)
+	if res.Bound > res.Value || (!res.Optimal && res.Bound == 0) {
+		t.Errorf("Unexpected bound %d for %d bins (optimal: %t)", res.Bound, res.Value, res.Optimal)
+	}
+}
+
+func TestInvalidInstances(t *testing.T) {
+	objects := []common.Objects{{Weight: 5, Value: 1}}
+	if _, err := multiple_knapsack.Knapsack(context.Background(), objects, []int{3, -1}, multiple_knapsack.Options{}); err == nil {
+		t.Errorf("Expected an error for a negative capacity")
+	}
+	if _, err := multiple_knapsack.BinPacking(context.Background(), objects, 4, multiple_knapsack.Options{}); err == nil {
+		t.Errorf("Expected an error for an object larger than the bins")
+	}
+}
@@ -0,0 +1,83 @@
+package reduction
+
+import (
+	"sort"
+
+	"../common"
+)
+
+/* prefix contient les sommes cumulées des poids et des valeurs des objets triés par rapport décroissant */
+type prefix struct {
+	order   []int
+	weights []int // weights[t] : poids des t premiers objets
+	values  []int
+	objects []common.Objects
+}
+
+func newPrefix(objects []common.Objects, order []int) *prefix {
+	p := &prefix{order: order, weights: make([]int, len(order)+1), values: make([]int, len(order)+1), objects: objects}
+	for t, i := range order {
+		p.weights[t+1] = p.weights[t] + objects[i].Weight
+		p.values[t+1] = p.values[t] + objects[i].Value
+	}
+	return p
+}
+
+/* greedy remplit le sac dans l'ordre en sautant les objets qui ne tiennent pas */
+func (p *prefix) greedy(capacity int) (int, []int) {
+	value, indices := 0, make([]int, 0)
+	for _, i := range p.order {
+		if p.objects[i].Weight <= capacity {
+			capacity -= p.objects[i].Weight
+			value += p.objects[i].Value
+			indices = append(indices, i)
+		}
+	}
+	return value, indices
+}
+
+/* breakItem renvoie le plus grand t tel que les t premiers objets, hormis celui en position skip, tiennent dans capacity */
+func (p *prefix) breakItem(capacity, skip int) int {
+	weight := func(t int) int {
+		if skip < t {
+			return p.weights[t] - p.weights[skip+1] + p.weights[skip]
+		}
+		return p.weights[t]
+	}
+	return sort.Search(len(p.order)+1, func(t int) bool { return weight(t) > capacity }) - 1
+}
+
+// bound renvoie la borne de Dantzig du problème privé de l'objet en position skip, ainsi que la valeur de la
+// solution réalisable formée des objets qui précèdent l'objet critique.
+func (p *prefix) bound(capacity, skip int) (int, int) {
+	t := p.breakItem(capacity, skip)
+	weight, value := p.weights[t], p.values[t]
+	if skip < t {
+		weight -= p.objects[p.order[skip]].Weight
+		value -= p.objects[p.order[skip]].Value
+	}
+
+	bound := value
+	if q := t; q < len(p.order) {
+		if q == skip {
+			q++
+		}
+		if q < len(p.order) {
+			obj := p.objects[p.order[q]]
+			bound += (capacity - weight) * obj.Value / obj.Weight
+		}
+	}
+	return bound, value
+}
+
+/* feasibleSet renvoie les indices de la solution réalisable dont bound renvoie la valeur */
+func (p *prefix) feasibleSet(capacity, skip int) []int {
+	t := p.breakItem(capacity, skip)
+	indices := make([]int, 0, t)
+	for k := 0; k < t; k++ {
+		if k != skip {
+			indices = append(indices, p.order[k])
+		}
+	}
+	return indices
+}
@@ -0,0 +1,175 @@
+package reduction
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"../common"
+)
+
+/* Reduction est une instance réduite accompagnée de quoi revenir à l'instance d'origine */
+type Reduction struct {
+	Instance  common.Instance // objets encore libres, capacité diminuée du poids des objets fixés à 1
+	Original  common.Instance
+	Mapping   []int // Mapping[k] : indice dans l'instance d'origine de l'objet libre k
+	Ones      []int // indices d'origine des objets fixés à 1
+	Zeros     []int // indices d'origine des objets fixés à 0
+	Incumbent []int // meilleure solution trouvée pendant la réduction (indices d'origine)
+	Lower     int   // valeur de Incumbent
+}
+
+/* Fixed renvoie le nombre d'objets fixés par la réduction */
+func (r Reduction) Fixed() int {
+	return len(r.Ones) + len(r.Zeros)
+}
+
+// Reduce applique la réduction d'Ingargiola-Korsh, sous la forme améliorée de Martello-Toth : pour chaque objet j,
+// on calcule la borne de Dantzig du problème où x_j vaut 1, puis celle où x_j vaut 0. Si l'une de ces bornes ne
+// dépasse pas la meilleure valeur connue L, aucune solution de valeur supérieure à L ne peut prendre cette valeur
+// et x_j est fixé à l'autre. Les solutions gloutonnes rencontrées pendant le calcul des bornes améliorent L au
+// passage. La solution de valeur L est conservée : elle est optimale si le problème réduit ne fait pas mieux.
+func Reduce(inst common.Instance) (Reduction, error) {
+	if inst.Capacity < 0 {
+		return Reduction{}, fmt.Errorf("Invalid capacity %d", inst.Capacity)
+	}
+
+	r := Reduction{Original: inst, Ones: make([]int, 0), Zeros: make([]int, 0), Incumbent: make([]int, 0)}
+
+	// Les cas triviaux sont fixés d'emblée ; les autres objets sont triés par rapport valeur/poids décroissant
+	var order []int
+	for _, i := range common.RatioOrder(inst.Objects) {
+		obj := inst.Objects[i]
+		switch {
+		case obj.Weight < 0:
+			return Reduction{}, fmt.Errorf("Object %d has negative weight %d", i, obj.Weight)
+		case obj.Value <= 0 || obj.Weight > inst.Capacity:
+			r.Zeros = append(r.Zeros, i)
+		case obj.Weight == 0:
+			r.Ones = append(r.Ones, i)
+		default:
+			order = append(order, i)
+		}
+	}
+
+	// Les objets de poids nul (déjà fixés à 1) s'ajoutent à toutes les solutions
+	zeroValue := 0
+	for _, i := range r.Ones {
+		zeroValue += inst.Objects[i].Value
+	}
+
+	p := newPrefix(inst.Objects, order)
+	r.Lower, r.Incumbent = p.greedy(inst.Capacity)
+	r.Lower += zeroValue
+	r.Incumbent = append(r.Incumbent, r.Ones...)
+
+	// Bornes avec x_j = 1 puis x_j = 0 pour chaque objet libre, relatives aux objets de order seulement
+	oneBounds := make([]int, len(order))
+	zeroBounds := make([]int, len(order))
+	for k, i := range order {
+		w, v := inst.Objects[i].Weight, inst.Objects[i].Value
+
+		bound, feasible := p.bound(inst.Capacity-w, k)
+		oneBounds[k] = bound + v
+		if feasible+v+zeroValue > r.Lower {
+			r.Lower = feasible + v + zeroValue
+			r.Incumbent = append(p.feasibleSet(inst.Capacity-w, k), i)
+			r.Incumbent = append(r.Incumbent, r.Ones...)
+		}
+
+		bound, _ = p.bound(inst.Capacity, k)
+		zeroBounds[k] = bound
+	}
+
+	fixedWeight := 0
+	for k, i := range order {
+		switch {
+		case oneBounds[k]+zeroValue <= r.Lower:
+			r.Zeros = append(r.Zeros, i)
+		case zeroBounds[k]+zeroValue <= r.Lower:
+			r.Ones = append(r.Ones, i)
+			fixedWeight += inst.Objects[i].Weight
+		default:
+			r.Mapping = append(r.Mapping, i)
+			r.Instance.Objects = append(r.Instance.Objects, inst.Objects[i])
+		}
+	}
+	r.Instance.Capacity = inst.Capacity - fixedWeight
+
+	sort.Ints(r.Ones)
+	sort.Ints(r.Zeros)
+	sort.Ints(r.Incumbent)
+	return r, nil
+}
+
+// Restore traduit un résultat obtenu sur l'instance réduite en résultat sur l'instance d'origine, en ajoutant
+// les objets fixés à 1. Si le problème réduit n'améliore pas la solution conservée (ou n'a pas de solution,
+// lorsque les objets fixés à 1 dépassent la capacité), c'est cette dernière qui est renvoyée.
+func (r Reduction) Restore(res common.Result) common.Result {
+	restored := common.NewResult(r.Original, r.Incumbent, res.Optimal)
+	if r.Instance.Capacity >= 0 {
+		indices := append([]int(nil), r.Ones...)
+		for _, k := range res.Indices {
+			indices = append(indices, r.Mapping[k])
+		}
+		if candidate := common.NewResult(r.Original, indices, res.Optimal); candidate.Value > restored.Value {
+			restored = candidate
+		}
+	}
+
+	if !res.Optimal && res.Bound > 0 {
+		restored.Bound = res.Bound + r.onesValue()
+		if restored.Bound < restored.Value {
+			restored.Bound = restored.Value
+		}
+	}
+	restored.Stats = res.Stats
+	restored.Stats.Fixed = r.Fixed()
+	return restored
+}
+
+func (r Reduction) onesValue() int {
+	value := 0
+	for _, i := range r.Ones {
+		value += r.Original.Objects[i].Value
+	}
+	return value
+}
+
+/* Solver applique la réduction avant de confier le problème réduit à un autre solveur */
+type Solver struct {
+	Inner common.Solver
+}
+
+/* Wrap ajoute la réduction préalable à n'importe quel solveur 0-1 */
+func Wrap(s common.Solver) common.Solver {
+	return Solver{Inner: s}
+}
+
+func (s Solver) Name() string {
+	return s.Inner.Name() + "_reduced"
+}
+
+func (s Solver) Solve(ctx context.Context, inst common.Instance) (common.Result, error) {
+	startTime := time.Now()
+	r, err := Reduce(inst)
+	if err != nil {
+		return common.Result{}, err
+	}
+
+	var res common.Result
+	if r.Instance.Capacity >= 0 && len(r.Instance.Objects) > 0 {
+		res, err = s.Inner.Solve(ctx, r.Instance)
+		if err != nil {
+			return common.Result{}, err
+		}
+	} else {
+		// Tout est fixé : la solution conservée est optimale
+		res = common.NewResult(r.Instance, nil, true)
+	}
+
+	restored := r.Restore(res)
+	restored.Stats.Duration = time.Since(startTime)
+	return restored, nil
+}
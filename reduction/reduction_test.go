@@ -0,0 +1,166 @@
+package reduction_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"../algo_prog_dynamique"
+	"../common"
+	"../reduction"
+	"../verification"
+)
+
+/* instance tire n objets faiblement corrélés, pour lesquels la réduction fixe la plupart des objets */
+func instance(random *rand.Rand, n int) common.Instance {
+	inst := common.Instance{Objects: make([]common.Objects, n)}
+	total := 0
+	for i := range inst.Objects {
+		w := 1 + random.Intn(100)
+		inst.Objects[i] = common.Objects{Weight: w, Value: 1 + random.Intn(100)}
+		total += w
+	}
+	inst.Capacity = total / 2
+	return inst
+}
+
+// bruteForce renvoie la valeur optimale et, pour chaque objet, s'il figure dans au moins une solution optimale
+// et s'il figure dans toutes.
+func bruteForce(inst common.Instance) (int, []bool, []bool) {
+	n := len(inst.Objects)
+	best, masks := -1, []int(nil)
+	for mask := 0; mask < 1<<n; mask++ {
+		weight, value := 0, 0
+		for i, obj := range inst.Objects {
+			if mask&(1<<i) != 0 {
+				weight += obj.Weight
+				value += obj.Value
+			}
+		}
+		if weight > inst.Capacity || value < best {
+			continue
+		}
+		if value > best {
+			best, masks = value, nil
+		}
+		masks = append(masks, mask)
+	}
+
+	inSome, inAll := make([]bool, n), make([]bool, n)
+	for i := range inAll {
+		inAll[i] = true
+	}
+	for _, mask := range masks {
+		for i := range inSome {
+			inSome[i] = inSome[i] || mask&(1<<i) != 0
+			inAll[i] = inAll[i] && mask&(1<<i) != 0
+		}
+	}
+	return best, inSome, inAll
+}
+
+func TestReduceKeepsAnOptimalSolution(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 100; trial++ {
+		inst := instance(random, 1+random.Intn(14))
+		optimum, inSome, inAll := bruteForce(inst)
+
+		r, err := reduction.Reduce(inst)
+		if err != nil {
+			t.Fatalf("Trial %d: %v", trial, err)
+		}
+		if r.Fixed()+len(r.Mapping) != len(inst.Objects) {
+			t.Errorf("Trial %d: %d fixed and %d free objects out of %d", trial, r.Fixed(), len(r.Mapping), len(inst.Objects))
+		}
+		incumbent := common.NewResult(inst, r.Incumbent, false)
+		if incumbent.Value != r.Lower || incumbent.Weight > inst.Capacity {
+			t.Errorf("Trial %d: incumbent %+v does not match the lower bound %d", trial, incumbent, r.Lower)
+		}
+
+		// Tant que la solution conservée n'est pas optimale, les objets fixés à 1 appartiennent à toutes les
+		// solutions optimales et ceux fixés à 0 n'appartiennent à aucune
+		if r.Lower < optimum {
+			for _, i := range r.Zeros {
+				if inSome[i] {
+					t.Errorf("Trial %d: object %d fixed to 0 belongs to an optimal solution", trial, i)
+				}
+			}
+			for _, i := range r.Ones {
+				if !inAll[i] {
+					t.Errorf("Trial %d: object %d fixed to 1 is missing from an optimal solution", trial, i)
+				}
+			}
+		}
+
+		// Le problème réduit n'a pas de solution quand les objets fixés à 1 dépassent la capacité
+		var reduced common.Result
+		if r.Instance.Capacity >= 0 {
+			_, indices := algo_prog_dynamique.KnapsackIndices(r.Instance.Objects, r.Instance.Capacity)
+			reduced = common.NewResult(r.Instance, indices, true)
+		}
+		restored := r.Restore(reduced)
+		if restored.Value != optimum || restored.Stats.Fixed != r.Fixed() {
+			t.Errorf("Trial %d: restored value %d, optimum %d", trial, restored.Value, optimum)
+		}
+		if err := verification.Check(inst, restored); err != nil {
+			t.Errorf("Trial %d: %v", trial, err)
+		}
+	}
+}
+
+func TestWrappedSolver(t *testing.T) {
+	solver := reduction.Wrap(algo_prog_dynamique.Solver{})
+	if solver.Name() != "dp_reduced" {
+		t.Errorf("Unexpected name %q", solver.Name())
+	}
+
+	random := rand.New(rand.NewSource(2))
+	fixed := 0
+	for trial := 0; trial < 50; trial++ {
+		inst := instance(random, 30)
+		res, err := solver.Solve(context.Background(), inst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		optimum, _ := algo_prog_dynamique.KnapsackIndices(inst.Objects, inst.Capacity)
+		if !res.Optimal || res.Value != optimum {
+			t.Errorf("Trial %d: value %d (optimal: %t), optimum %d", trial, res.Value, res.Optimal, optimum)
+		}
+		if err := verification.Check(inst, res); err != nil {
+			t.Errorf("Trial %d: %v", trial, err)
+		}
+		fixed += res.Stats.Fixed
+	}
+	if fixed == 0 {
+		t.Errorf("The reduction never fixed an object")
+	}
+}
+
+func TestTrivialObjects(t *testing.T) {
+	inst := common.Instance{Objects: []common.Objects{
+		{Weight: 0, Value: 3},  // toujours pris
+		{Weight: 20, Value: 9}, // trop lourd
+		{Weight: 2, Value: 0},  // sans valeur
+		{Weight: 4, Value: 5},
+	}, Capacity: 10}
+
+	r, err := reduction.Reduce(inst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := r.Restore(common.NewResult(r.Instance, make([]int, len(r.Instance.Objects)), true))
+	if res.Value != 8 || r.Fixed() != 4 {
+		t.Errorf("Expected every object fixed and value 8, got %d fixed and %+v", r.Fixed(), res)
+	}
+}
+
+func TestInvalidInstances(t *testing.T) {
+	for _, inst := range []common.Instance{
+		{Capacity: -1},
+		{Objects: []common.Objects{{Weight: -1, Value: 1}}, Capacity: 5},
+	} {
+		if _, err := reduction.Reduce(inst); err == nil {
+			t.Errorf("Expected an error for %+v", inst)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package reserch_exhastive
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"../algorithme_glouton"
+	"../common"
+)
+
+/* prefixTask est un sous-arbre de la recherche : les objets 0..len(taken)-1 sont déjà fixés */
+type prefixTask struct {
+	taken  []int
+	weight int
+	value  int
+}
+
+/* taskResult est la meilleure solution trouvée dans un sous-arbre */
+type taskResult struct {
+	value       int
+	indices     []int
+	nodes       int64
+	interrupted bool
+}
+
+/* parallelSearch contient les données partagées par les workers */
+type parallelSearch struct {
+	ctx       context.Context
+	objects   []common.Objects
+	capacity  int
+	remaining []int // remaining[i] : somme des valeurs positives des objets i..n-1
+	best      int64 // meilleure valeur connue de tous les workers, lue et écrite atomiquement
+	explored  int64 // noeuds des tâches terminées, pour le suivi de la progression
+	progress  *common.Reporter
+}
+
+// KnapsackParallel explore le même arbre que KnapsackIndices, réparti entre workers goroutines (runtime.NumCPU()
+// si workers <= 0) : les premiers objets sont fixés de toutes les façons possibles, chaque préfixe formant une
+// tâche. La meilleure valeur connue est partagée atomiquement et un sous-arbre n'est élagué que si sa borne lui
+// est strictement inférieure, si bien que chaque tâche trouve toutes les solutions optimales qu'elle contient. Le
+// résultat est celui de la recherche séquentielle : parmi les solutions optimales, la première dans l'ordre de
+// l'exploration, quel que soit l'ordonnancement des goroutines.
+func KnapsackParallel(objects []common.Objects, capacity, workers int) (int, []int, int64) {
+	value, indices, nodes, _ := KnapsackParallelContext(context.Background(), objects, capacity, workers)
+	return value, indices, nodes
+}
+
+// KnapsackParallelContext est KnapsackParallel interruptible : si le contexte est annulé, chaque worker
+// abandonne sa tâche, la meilleure solution déjà trouvée est renvoyée et le dernier résultat est vrai.
+func KnapsackParallelContext(ctx context.Context, objects []common.Objects, capacity, workers int) (int, []int, int64, bool) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	n := len(objects)
+	s := &parallelSearch{ctx: ctx, objects: objects, capacity: capacity, remaining: make([]int, n+1), progress: common.NewReporter(ctx)}
+	for i := n - 1; i >= 0; i-- {
+		s.remaining[i] = s.remaining[i+1]
+		if objects[i].Value > 0 {
+			s.remaining[i] += objects[i].Value
+		}
+	}
+
+	// Environ 16 tâches par worker pour équilibrer la charge
+	depth := 4
+	for w := workers; w > 1; w /= 2 {
+		depth++
+	}
+	if depth > n {
+		depth = n
+	}
+
+	var tasks []prefixTask
+	var nodes int64
+	s.prefixes(depth, 0, make([]int, 0, depth), 0, 0, &tasks, &nodes)
+	s.explored = nodes
+
+	results := make([]taskResult, len(tasks))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range jobs {
+				results[k] = s.run(depth, tasks[k])
+			}
+		}()
+	}
+	for k := range tasks {
+		jobs <- k
+	}
+	close(jobs)
+	wg.Wait()
+
+	// Les tâches sont dans l'ordre de l'exploration séquentielle : la première de valeur maximale l'emporte
+	bestValue, bestIndices := 0, make([]int, 0)
+	interrupted := false
+	for _, r := range results {
+		nodes += r.nodes
+		interrupted = interrupted || r.interrupted
+		if r.value > bestValue {
+			bestValue, bestIndices = r.value, r.indices
+		}
+	}
+	s.progress.Finish(common.Progress{Solver: "exhaustive_parallel", Nodes: nodes, Best: bestValue})
+	return bestValue, bestIndices, nodes, interrupted
+}
+
+/* prefixes énumère les préfixes réalisables de longueur depth dans l'ordre de l'exploration (objet pris d'abord) */
+func (s *parallelSearch) prefixes(depth, index int, taken []int, weight, value int, tasks *[]prefixTask, nodes *int64) {
+	if index == depth {
+		*tasks = append(*tasks, prefixTask{taken: append([]int(nil), taken...), weight: weight, value: value})
+		return
+	}
+	*nodes++
+
+	obj := s.objects[index]
+	if weight+obj.Weight <= s.capacity {
+		s.prefixes(depth, index+1, append(taken, index), weight+obj.Weight, value+obj.Value, tasks, nodes)
+	}
+	s.prefixes(depth, index+1, taken, weight, value, tasks, nodes)
+}
+
+/* run explore le sous-arbre d'une tâche */
+func (s *parallelSearch) run(depth int, t prefixTask) taskResult {
+	var r taskResult
+	subset := append(make([]int, 0, len(s.objects)), t.taken...)
+	poll := common.NewPoll(s.ctx)
+	poll.Reporter = s.progress
+	s.branch(depth, t.weight, t.value, subset, &r, poll)
+	r.interrupted = poll.Stopped()
+	atomic.AddInt64(&s.explored, r.nodes)
+	return r
+}
+
+/* branch est la recherche séquentielle de GenerateSubsets, avec poids et valeur tenus à jour et élagage par la borne */
+func (s *parallelSearch) branch(index, weight, value int, subset []int, r *taskResult, poll *common.Poll) {
+	if poll.Done() {
+		return
+	}
+	if poll.Due() {
+		poll.Report(common.Progress{
+			Solver: "exhaustive_parallel",
+			Nodes:  atomic.LoadInt64(&s.explored) + r.nodes,
+			Best:   int(atomic.LoadInt64(&s.best)),
+		})
+	}
+	r.nodes++
+	if value+s.remaining[index] < int(atomic.LoadInt64(&s.best)) {
+		return
+	}
+
+	if index == len(s.objects) {
+		if value > r.value {
+			r.value = value
+			r.indices = append(r.indices[:0], subset...)
+			s.raiseBest(value)
+		}
+		return
+	}
+
+	obj := s.objects[index]
+	if weight+obj.Weight <= s.capacity {
+		s.branch(index+1, weight+obj.Weight, value+obj.Value, append(subset, index), r, poll)
+	}
+	s.branch(index+1, weight, value, subset, r, poll)
+}
+
+/* raiseBest remplace la meilleure valeur partagée si value est plus grande */
+func (s *parallelSearch) raiseBest(value int) {
+	for {
+		current := atomic.LoadInt64(&s.best)
+		if int64(value) <= current || atomic.CompareAndSwapInt64(&s.best, current, int64(value)) {
+			return
+		}
+	}
+}
+
+/* ParallelSolver adapte la recherche exhaustive parallèle à l'interface common.Solver */
+type ParallelSolver struct {
+	Workers int // nombre de goroutines, runtime.NumCPU() si 0
+}
+
+func init() {
+	common.Register(ParallelSolver{})
+}
+
+func (ParallelSolver) Name() string {
+	return "exhaustive_parallel"
+}
+
+func (s ParallelSolver) Solve(ctx context.Context, inst common.Instance) (common.Result, error) {
+	startTime := time.Now()
+	_, indices, nodes, interrupted := KnapsackParallelContext(ctx, inst.Objects, inst.Capacity, s.Workers)
+
+	res := common.NewResult(inst, indices, true)
+	if interrupted {
+		res.Interrupt(algorithme_glouton.FractionalKnapsack(inst.Objects, inst.Capacity).Bound)
+		algorithme_glouton.Improve(&res, inst)
+	}
+	if res.Optimal {
+		res.Certificate = common.DualCertificate(inst, res.Value)
+	}
+	res.Stats.Nodes = nodes
+	res.Stats.Duration = time.Since(startTime)
+	return res, nil
+}
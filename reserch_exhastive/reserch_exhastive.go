@@ -1,48 +1,118 @@
 package reserch_exhastive
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"time"
 
+	"../algorithme_glouton"
 	"../common"
 )
 
 /* Knapsack résout le problème du sac à dos en utilisant une recherche exhaustive et retourne la meilleure valeur et les objets qui peuvent être emportés dans le sac.*/
 
 func Knapsack(objects []common.Objects, capacity int) (int, []common.Objects) {
+	bestValue, bestIndices, _ := KnapsackIndices(objects, capacity)
+
+	bestSubset := make([]common.Objects, 0, len(bestIndices))
+	for _, i := range bestIndices {
+		bestSubset = append(bestSubset, objects[i])
+	}
+
+	return bestValue, bestSubset
+}
+
+/* KnapsackIndices renvoie la meilleure valeur, les indices des objets retenus et le nombre de noeuds explorés. */
+
+func KnapsackIndices(objects []common.Objects, capacity int) (int, []int, int64) {
+	bestValue, bestSubset, nodes, _ := KnapsackContext(context.Background(), objects, capacity)
+	return bestValue, bestSubset, nodes
+}
+
+/* KnapsackContext est KnapsackIndices interruptible : si le contexte est annulé, la meilleure solution déjà trouvée est renvoyée et le dernier résultat est vrai. */
+
+func KnapsackContext(ctx context.Context, objects []common.Objects, capacity int) (int, []int, int64, bool) {
 	bestValue := 0
-	bestSubset := make([]common.Objects, 0)
+	bestSubset := make([]int, 0)
+	var nodes int64
+	poll := common.NewPoll(ctx)
 
 	// Générer tous les sous-ensembles possibles et trouver celui avec la meilleure valeur
-	GenerateSubsets(objects, capacity, 0, make([]common.Objects, 0), &bestValue, &bestSubset)
+	GenerateSubsets(objects, capacity, 0, make([]int, 0), &bestValue, &bestSubset, &nodes, poll)
+	poll.Finish(common.Progress{Solver: "exhaustive", Nodes: nodes, Best: bestValue})
 
-	return bestValue, bestSubset
+	return bestValue, bestSubset, nodes, poll.Stopped()
 }
 
-/* GenerateSubsets génère tous les sous-ensembles possibles d'objets et met à jour la meilleure valeur et le meilleur sous-ensemble. */
+/* GenerateSubsets génère tous les sous-ensembles possibles d'objets (par leurs indices) et met à jour la meilleure valeur et le meilleur sous-ensemble. La génération s'arrête dès que poll (s'il n'est pas nil) signale une annulation, et lui transmet sa progression. */
 
-func GenerateSubsets(objects []common.Objects, capacity, index int, subset []common.Objects, bestValue *int, bestSubset *[]common.Objects) {
+func GenerateSubsets(objects []common.Objects, capacity, index int, subset []int, bestValue *int, bestSubset *[]int, nodes *int64, poll *common.Poll) {
+	if poll != nil {
+		if poll.Done() {
+			return
+		}
+		if poll.Due() {
+			poll.Report(common.Progress{Solver: "exhaustive", Nodes: *nodes, Best: *bestValue})
+		}
+	}
+	*nodes++
 	if index == len(objects) {
 		// Calculer la valeur du sous-ensemble généré
-		subsetValue := ComputeSubsetValue(subset)
+		subsetValue := 0
+		for _, i := range subset {
+			subsetValue += objects[i].Value
+		}
 		if subsetValue > *bestValue {
 			// Mettre à jour la meilleure valeur et le meilleur sous-ensemble
 			*bestValue = subsetValue
-			*bestSubset = make([]common.Objects, len(subset))
+			*bestSubset = make([]int, len(subset))
 			copy(*bestSubset, subset)
 		}
 		return
 	}
 
-	if SubsetWeight(subset)+objects[index].Weight <= capacity {
-		subset = append(subset, objects[index])
-		GenerateSubsets(objects, capacity, index+1, subset, bestValue, bestSubset)
+	subsetWeight := 0
+	for _, i := range subset {
+		subsetWeight += objects[i].Weight
+	}
+
+	if subsetWeight+objects[index].Weight <= capacity {
+		subset = append(subset, index)
+		GenerateSubsets(objects, capacity, index+1, subset, bestValue, bestSubset, nodes, poll)
 		subset = subset[:len(subset)-1]
 	}
 
-	GenerateSubsets(objects, capacity, index+1, subset, bestValue, bestSubset)
+	GenerateSubsets(objects, capacity, index+1, subset, bestValue, bestSubset, nodes, poll)
+}
+
+/* Solver adapte la recherche exhaustive à l'interface common.Solver */
+type Solver struct{}
+
+func init() {
+	common.Register(Solver{})
+}
+
+func (Solver) Name() string {
+	return "exhaustive"
+}
+
+func (Solver) Solve(ctx context.Context, inst common.Instance) (common.Result, error) {
+	startTime := time.Now()
+	_, indices, nodes, interrupted := KnapsackContext(ctx, inst.Objects, inst.Capacity)
+
+	res := common.NewResult(inst, indices, true)
+	if interrupted {
+		res.Interrupt(algorithme_glouton.FractionalKnapsack(inst.Objects, inst.Capacity).Bound)
+		algorithme_glouton.Improve(&res, inst)
+	}
+	if res.Optimal {
+		res.Certificate = common.DualCertificate(inst, res.Value)
+	}
+	res.Stats.Nodes = nodes
+	res.Stats.Duration = time.Since(startTime)
+	return res, nil
 }
 
 /* SubsetWeight calcule le poids total d'un sous-ensemble d'objets. */
@@ -0,0 +1,65 @@
+package reserch_exhastive_test
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"../algo_prog_dynamique"
+	"../common"
+	"../reserch_exhastive"
+	"../verification"
+)
+
+func randomObjects(random *rand.Rand, n int) ([]common.Objects, int) {
+	objects := make([]common.Objects, n)
+	total := 0
+	for i := range objects {
+		objects[i] = common.Objects{Weight: 1 + random.Intn(50), Value: 1 + random.Intn(50)}
+		total += objects[i].Weight
+	}
+	return objects, total / 2
+}
+
+func TestSequentialAndParallel(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 40; trial++ {
+		objects, capacity := randomObjects(random, random.Intn(16))
+		inst := common.Instance{Objects: objects, Capacity: capacity}
+		optimum := algo_prog_dynamique.KnapsackValue(objects, capacity)
+
+		value, indices, _ := reserch_exhastive.KnapsackIndices(objects, capacity)
+		if res := common.NewResult(inst, indices, true); value != optimum || res.Value != value || res.Weight > capacity {
+			t.Errorf("Trial %d: value %d, indices give %d (weight %d), optimum %d", trial, value, res.Value, res.Weight, optimum)
+		}
+
+		// La recherche parallèle renvoie la même solution que la recherche séquentielle, quel que soit le nombre de workers
+		for _, workers := range []int{1, 3, 0} {
+			parallel, parallelIndices, _ := reserch_exhastive.KnapsackParallel(objects, capacity, workers)
+			if parallel != value || !reflect.DeepEqual(parallelIndices, indices) {
+				t.Errorf("Trial %d, %d workers: %d %v, sequential %d %v", trial, workers, parallel, parallelIndices, value, indices)
+			}
+		}
+	}
+}
+
+func TestInterrupted(t *testing.T) {
+	objects, capacity := randomObjects(rand.New(rand.NewSource(2)), 40)
+	inst := common.Instance{Objects: objects, Capacity: capacity}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, solver := range []common.Solver{reserch_exhastive.Solver{}, reserch_exhastive.ParallelSolver{}} {
+		res, err := solver.Solve(ctx, inst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := verification.Check(inst, res); err != nil {
+			t.Errorf("%s: %v", solver.Name(), err)
+		}
+		if res.Optimal || !res.Stats.Interrupted {
+			t.Errorf("%s: expected an interrupted result, got %+v", solver.Name(), res.Stats)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package subset_sum
+
+import (
+	"context"
+	"math/big"
+	"math/rand"
+	"time"
+)
+
+/* Options paramètre l'algorithme probabiliste de Howgrave-Graham et Joux */
+type Options struct {
+	Seed           int64 // graine du générateur aléatoire, 0 : dépend de l'heure
+	Attempts       int   // nombre de tirages (permutation, résidu) par poids de Hamming, 0 : 32
+	HammingWeights []int // poids de Hamming possibles de la solution, nil : tous
+	MaxModulus     int64 // borne sur le module M, 0 : 1<<20
+}
+
+// HowgraveGrahamJoux résout le problème de somme de sous-ensemble par la technique des représentations :
+// une solution x de poids l s'écrit de C(l, l/2) façons x = x1 + x2 avec x1, x2 de poids l/2 à supports
+// disjoints. On ne garde que les x1 tels que <a,x1> = R (mod M) et les x2 tels que <a,x2> = t-R (mod M),
+// avec M de l'ordre du nombre de représentations, ce qui réduit la taille des listes tout en conservant
+// en moyenne une représentation de la solution. Chaque liste est elle-même construite par fusion de deux
+// demi-listes sur une partition aléatoire des positions. L'algorithme est probabiliste : ErrNoSolution
+// signifie qu'aucune solution n'a été trouvée après Attempts tirages, pas qu'il n'en existe aucune. Le contexte
+// est consulté avant chaque tirage.
+func HowgraveGrahamJoux(ctx context.Context, weights []*big.Int, target *big.Int, opts Options) ([]byte, error) {
+	if err := checkInput(weights, target); err != nil {
+		return nil, err
+	}
+
+	seed := opts.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	random := rand.New(rand.NewSource(seed))
+
+	attempts := opts.Attempts
+	if attempts <= 0 {
+		attempts = 32
+	}
+	maxModulus := opts.MaxModulus
+	if maxModulus <= 0 {
+		maxModulus = 1 << 20
+	}
+
+	n := len(weights)
+	hammingWeights := opts.HammingWeights
+	if hammingWeights == nil {
+		for l := 0; l <= n; l++ {
+			hammingWeights = append(hammingWeights, l)
+		}
+	}
+
+	for _, l := range hammingWeights {
+		if l < 0 || l > n {
+			continue
+		}
+
+		// Le module est de l'ordre du nombre de représentations C(l, l/2)
+		modulus := new(big.Int).Binomial(int64(l), int64(l/2))
+		if modulus.Cmp(big.NewInt(maxModulus)) > 0 {
+			modulus.SetInt64(maxModulus)
+		}
+
+		for attempt := 0; attempt < attempts; attempt++ {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			perm := random.Perm(n)
+			residue := new(big.Int).Rand(random, modulus)
+			complement := new(big.Int).Sub(target, residue)
+			complement.Mod(complement, modulus)
+
+			// La seconde liste découpe la permutation renversée : sinon, pour un poids impair, la position
+			// supplémentaire de x1 et de x2 tomberait toujours dans la même moitié (l = 2, n = 2 échouait toujours)
+			reversed := make([]int, n)
+			for i, p := range perm {
+				reversed[n-1-i] = p
+			}
+			list1 := representations(weights, perm, l/2, residue, modulus)
+			list2 := representations(weights, reversed, l-l/2, complement, modulus)
+
+			// Jointure exacte : <a,x1> + <a,x2> = t avec x1 et x2 à supports disjoints
+			bySum := make(map[string][]uint64, len(list2))
+			for _, s := range list2 {
+				key := s.sum.String()
+				bySum[key] = append(bySum[key], s.mask)
+			}
+
+			missing := new(big.Int)
+			for _, s1 := range list1 {
+				for _, mask2 := range bySum[missing.Sub(target, s1.sum).String()] {
+					if s1.mask&mask2 == 0 {
+						return maskToBits(s1.mask|mask2, n), nil
+					}
+				}
+			}
+		}
+	}
+
+	return nil, ErrNoSolution
+}
+
+// representations construit les vecteurs de poids de Hamming k dont la somme vaut residue modulo modulus,
+// en combinant k/2 positions de la première moitié de perm et k-k/2 positions de la seconde.
+func representations(weights []*big.Int, perm []int, k int, residue, modulus *big.Int) []subset {
+	half := len(perm) / 2
+	left := combinations(weights, perm[:half], k/2)
+	right := combinations(weights, perm[half:], k-k/2)
+
+	byResidue := make(map[string][]subset, len(right))
+	r := new(big.Int)
+	for _, s := range right {
+		key := r.Mod(s.sum, modulus).String()
+		byResidue[key] = append(byResidue[key], s)
+	}
+
+	var list []subset
+	for _, s := range left {
+		r.Sub(residue, s.sum)
+		r.Mod(r, modulus)
+		for _, t := range byResidue[r.String()] {
+			list = append(list, subset{new(big.Int).Add(s.sum, t.sum), s.mask | t.mask})
+		}
+	}
+
+	return list
+}
+
+/* combinations énumère les sous-ensembles de k positions parmi positions, avec leur somme */
+func combinations(weights []*big.Int, positions []int, k int) []subset {
+	var list []subset
+
+	var generate func(start, remaining int, current subset)
+	generate = func(start, remaining int, current subset) {
+		if remaining == 0 {
+			list = append(list, current)
+			return
+		}
+		for i := start; i <= len(positions)-remaining; i++ {
+			p := positions[i]
+			next := subset{new(big.Int).Add(current.sum, weights[p]), current.mask | 1<<uint(p)}
+			generate(i+1, remaining-1, next)
+		}
+	}
+
+	if k >= 0 && k <= len(positions) {
+		generate(0, k, subset{big.NewInt(0), 0})
+	}
+	return list
+}
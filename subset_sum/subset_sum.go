@@ -0,0 +1,210 @@
+package subset_sum
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"../common"
+	"../merkel_hellman"
+)
+
+/* MaxWeights est le nombre maximal de poids, chaque sous-ensemble étant codé sur un uint64 */
+const MaxWeights = 64
+
+var (
+	ErrNoSolution     = errors.New("No subset of the weights sums to the target")
+	ErrTooManyWeights = fmt.Errorf("Subset-sum solvers support at most %d weights", MaxWeights)
+)
+
+/* subset représente un sous-ensemble de poids et sa somme */
+type subset struct {
+	sum  *big.Int
+	mask uint64
+}
+
+/* Fonction qui vérifie les paramètres communs à tous les solveurs */
+func checkInput(weights []*big.Int, target *big.Int) error {
+	if len(weights) > MaxWeights {
+		return fmt.Errorf("%w, got %d", ErrTooManyWeights, len(weights))
+	}
+	if target == nil || target.Sign() < 0 {
+		return errors.New("Target must be a non-negative integer")
+	}
+	for i, w := range weights {
+		if w == nil || w.Sign() < 0 {
+			return fmt.Errorf("Weight %d must be a non-negative integer", i)
+		}
+	}
+	return nil
+}
+
+/* Fonction qui convertit un masque en vecteur de bits de longueur n */
+func maskToBits(mask uint64, n int) []byte {
+	bits := make([]byte, n)
+	for i := 0; i < n; i++ {
+		if mask&(1<<uint(i)) != 0 {
+			bits[i] = 1
+		}
+	}
+	return bits
+}
+
+/* Fonction qui calcule la somme des poids sélectionnés par un masque */
+func maskSum(weights []*big.Int, mask uint64) *big.Int {
+	sum := big.NewInt(0)
+	for i, w := range weights {
+		if mask&(1<<uint(i)) != 0 {
+			sum.Add(sum, w)
+		}
+	}
+	return sum
+}
+
+/* allSubsets énumère les 2^(to-from) sous-ensembles des poids [from, to), triés par somme croissante */
+func allSubsets(weights []*big.Int, from, to int) []subset {
+	list := []subset{{big.NewInt(0), 0}}
+	for i := from; i < to; i++ {
+		bit := uint64(1) << uint(i)
+		for _, s := range list {
+			list = append(list, subset{new(big.Int).Add(s.sum, weights[i]), s.mask | bit})
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].sum.Cmp(list[j].sum) < 0
+	})
+	return list
+}
+
+/* pair désigne la somme list1[i] + list2[j] dans une file de priorité */
+type pair struct {
+	i, j int
+	sum  *big.Int
+}
+
+/* pairHeap est une file de priorité de sommes, croissante ou décroissante */
+type pairHeap struct {
+	pairs      []pair
+	descending bool
+}
+
+func (h *pairHeap) Len() int { return len(h.pairs) }
+func (h *pairHeap) Less(a, b int) bool {
+	if h.descending {
+		return h.pairs[a].sum.Cmp(h.pairs[b].sum) > 0
+	}
+	return h.pairs[a].sum.Cmp(h.pairs[b].sum) < 0
+}
+func (h *pairHeap) Swap(a, b int)      { h.pairs[a], h.pairs[b] = h.pairs[b], h.pairs[a] }
+func (h *pairHeap) Push(x interface{}) { h.pairs = append(h.pairs, x.(pair)) }
+func (h *pairHeap) Pop() interface{} {
+	last := h.pairs[len(h.pairs)-1]
+	h.pairs = h.pairs[:len(h.pairs)-1]
+	return last
+}
+
+/* sumStream énumère les sommes list1[i] + list2[j] dans l'ordre, en mémoire O(len(list1)) */
+type sumStream struct {
+	list1, list2 []subset
+	heap         *pairHeap
+}
+
+func newSumStream(list1, list2 []subset, descending bool) *sumStream {
+	s := &sumStream{list1: list1, list2: list2, heap: &pairHeap{descending: descending}}
+	start := 0
+	if descending {
+		start = len(list2) - 1
+	}
+	for i := range list1 {
+		s.heap.pairs = append(s.heap.pairs, pair{i, start, new(big.Int).Add(list1[i].sum, list2[start].sum)})
+	}
+	heap.Init(s.heap)
+	return s
+}
+
+func (s *sumStream) empty() bool {
+	return s.heap.Len() == 0
+}
+
+func (s *sumStream) peek() pair {
+	return s.heap.pairs[0]
+}
+
+func (s *sumStream) mask(p pair) uint64 {
+	return s.list1[p.i].mask | s.list2[p.j].mask
+}
+
+/* Fonction qui passe à la somme suivante du flux */
+func (s *sumStream) advance() {
+	p := heap.Pop(s.heap).(pair)
+	if s.heap.descending {
+		p.j--
+	} else {
+		p.j++
+	}
+	if p.j >= 0 && p.j < len(s.list2) {
+		p.sum = new(big.Int).Add(s.list1[p.i].sum, s.list2[p.j].sum)
+		heap.Push(s.heap, p)
+	}
+}
+
+// SchroeppelShamir résout le problème du sac à dos (somme de sous-ensemble) sur des entiers arbitraires :
+// trouver x dans {0,1}^n tel que somme(x[i]*weights[i]) = target. Les poids sont coupés en quatre quarts,
+// les sommes des deux premiers sont parcourues en ordre croissant et celles des deux derniers en ordre
+// décroissant à l'aide de files de priorité : temps O(2^(n/2)), mémoire O(2^(n/4)). Si le contexte est annulé
+// pendant le parcours, l'erreur du contexte est renvoyée.
+func SchroeppelShamir(ctx context.Context, weights []*big.Int, target *big.Int) ([]byte, error) {
+	if err := checkInput(weights, target); err != nil {
+		return nil, err
+	}
+
+	n := len(weights)
+	q1, q2, q3 := n/4, n/2, n/2+(n-n/2)/2
+
+	left := newSumStream(allSubsets(weights, 0, q1), allSubsets(weights, q1, q2), false)
+	right := newSumStream(allSubsets(weights, q2, q3), allSubsets(weights, q3, n), true)
+
+	sum := new(big.Int)
+	poll := common.NewPoll(ctx)
+	for !left.empty() && !right.empty() {
+		if poll.Done() {
+			return nil, ctx.Err()
+		}
+		l, r := left.peek(), right.peek()
+		switch sum.Add(l.sum, r.sum).Cmp(target) {
+		case 0:
+			return maskToBits(left.mask(l)|right.mask(r), n), nil
+		case -1:
+			left.advance()
+		default:
+			right.advance()
+		}
+	}
+
+	return nil, ErrNoSolution
+}
+
+// AttackMerkleHellman retrouve le message chiffré c à partir de la seule clé publique, en résolvant
+// directement le problème de somme de sous-ensemble par l'algorithme de Schroeppel-Shamir. Les sous-ensembles
+// étant codés sur un uint64, la clé doit avoir au plus MaxWeights poids : merkel_hellman.GenerateKeys(byteSize)
+// en produit ceil(sqrt(4·byteSize)), soit au plus 1024 octets (la clé de tools.GenerateKeys, 10000 octets et
+// 200 poids, est refusée avec ErrTooManyWeights). En pratique le temps O(2^(n/2)) limite l'attaque à une
+// cinquantaine de poids.
+func AttackMerkleHellman(ctx context.Context, pubKey *merkel_hellman.PublicKey, c *big.Int) (string, error) {
+	bits, err := SchroeppelShamir(ctx, pubKey.M, c)
+	if err != nil {
+		return "", err
+	}
+
+	// Ajuster la taille de la séquence de bits pour qu'elle soit un multiple de 8
+	bitPadding := 8 - (len(bits) % 8)
+	if bitPadding < 8 {
+		bits = append(bits, make([]byte, bitPadding)...)
+	}
+
+	return merkel_hellman.BinaryToString(bits)
+}
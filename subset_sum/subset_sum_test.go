@@ -0,0 +1,108 @@
+package subset_sum_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"../merkel_hellman"
+	"../subset_sum"
+)
+
+/* instance tire n poids de bits bits et une cible formée d'un sous-ensemble aléatoire */
+func instance(random *rand.Rand, n int, bits uint) ([]*big.Int, *big.Int) {
+	weights := make([]*big.Int, n)
+	target := new(big.Int)
+	for i := range weights {
+		weights[i] = new(big.Int).Rand(random, new(big.Int).Lsh(big.NewInt(1), bits))
+		if random.Intn(2) == 1 {
+			target.Add(target, weights[i])
+		}
+	}
+	return weights, target
+}
+
+func checkSolution(t *testing.T, name string, weights []*big.Int, target *big.Int, bits []byte) {
+	t.Helper()
+	if len(bits) != len(weights) {
+		t.Fatalf("%s: expected %d bits, got %d", name, len(weights), len(bits))
+	}
+	sum := new(big.Int)
+	for i, b := range bits {
+		if b == 1 {
+			sum.Add(sum, weights[i])
+		}
+	}
+	if sum.Cmp(target) != 0 {
+		t.Errorf("%s: subset sums to %s, expected %s", name, sum, target)
+	}
+}
+
+func TestSolversOnRandomInstances(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 40; trial++ {
+		weights, target := instance(random, 1+trial%20, 40)
+
+		bits, err := subset_sum.SchroeppelShamir(context.Background(), weights, target)
+		if err != nil {
+			t.Fatalf("Schroeppel-Shamir, trial %d: %v", trial, err)
+		}
+		checkSolution(t, "Schroeppel-Shamir", weights, target, bits)
+
+		bits, err = subset_sum.HowgraveGrahamJoux(context.Background(), weights, target, subset_sum.Options{Seed: int64(trial + 1)})
+		if err != nil {
+			t.Fatalf("Howgrave-Graham-Joux, trial %d: %v", trial, err)
+		}
+		checkSolution(t, "Howgrave-Graham-Joux", weights, target, bits)
+	}
+}
+
+func TestInvalidInstances(t *testing.T) {
+	weights := []*big.Int{big.NewInt(3), big.NewInt(5), big.NewInt(9)}
+	if _, err := subset_sum.SchroeppelShamir(context.Background(), weights, big.NewInt(18)); err != subset_sum.ErrNoSolution {
+		t.Errorf("Expected ErrNoSolution for an unreachable target, got %v", err)
+	}
+
+	many := make([]*big.Int, subset_sum.MaxWeights+1)
+	for i := range many {
+		many[i] = big.NewInt(int64(i + 1))
+	}
+	if _, err := subset_sum.SchroeppelShamir(context.Background(), many, big.NewInt(1)); !errors.Is(err, subset_sum.ErrTooManyWeights) {
+		t.Errorf("Expected ErrTooManyWeights, got %v", err)
+	}
+}
+
+func TestAttackMerkleHellman(t *testing.T) {
+	// 64 octets donnent une clé de 16 poids, de quoi chiffrer deux caractères
+	_, pubKey, err := merkel_hellman.GenerateKeys(64, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pubKey.M) != 16 {
+		t.Fatalf("Expected a 16-weight key, got %d weights", len(pubKey.M))
+	}
+
+	for _, message := range []string{"Go", "ok", "Hi"} {
+		c, err := merkel_hellman.Encrypt(pubKey, message)
+		if err != nil {
+			t.Fatal(err)
+		}
+		plaintext, err := subset_sum.AttackMerkleHellman(context.Background(), pubKey, c)
+		if err != nil {
+			t.Fatalf("Attack on %q: %v", message, err)
+		}
+		if plaintext != message {
+			t.Errorf("Attack recovered %q, expected %q", plaintext, message)
+		}
+	}
+
+	_, largeKey, err := merkel_hellman.GenerateKeys(10000, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := subset_sum.AttackMerkleHellman(context.Background(), largeKey, big.NewInt(1)); !errors.Is(err, subset_sum.ErrTooManyWeights) {
+		t.Errorf("Expected ErrTooManyWeights for a %d-weight key, got %v", len(largeKey.M), err)
+	}
+}
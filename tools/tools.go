@@ -1,42 +1,118 @@
 package tools
 
 import (
+	"context"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"log"
+	"os"
 	"runtime"
-	"sort"
+	"time"
 
 	"../algo_prog_dynamique"
 	"../algorithme_glouton"
+	_ "../bounded_knapsack"
+	_ "../branch_and_bound"
 	"../common"
+	"../formats"
+	_ "../fptas"
+	_ "../meet_in_the_middle"
 	"../merkel_hellman"
+	_ "../minknap"
+	_ "../multidimensional"
+	_ "../multiple_choice"
+	"../multiple_knapsack"
+	"../reduction"
 	"../reserch_exhastive"
+	"../verification"
 )
 
-// Change algorithme_glouton.Objects to common.Objects
-func LoadDataFromFile(filename string) ([]common.Objects, error) {
-	fileBytes, err := ioutil.ReadFile(filename)
-	if err != nil {
-		return nil, err
+/* SolverTimeout est la durée accordée à chaque solveur par PerformKnapsackBenchmark, 0 : pas de limite */
+var SolverTimeout = 10 * time.Second
+
+/* Reduce fait précéder chaque solveur de PerformKnapsackBenchmark de la réduction d'Ingargiola-Korsh */
+var Reduce = false
+
+/* ProgressInterval est l'intervalle entre deux lignes de progression affichées, 0 : pas d'affichage */
+var ProgressInterval = 500 * time.Millisecond
+
+/* WithProgress ajoute au contexte l'affichage de la progression sur la sortie d'erreur, si ProgressInterval n'est pas nul */
+func WithProgress(ctx context.Context) context.Context {
+	if ProgressInterval <= 0 {
+		return ctx
 	}
+	return common.WithProgress(ctx, ProgressInterval, PrintProgress)
+}
+
+// PrintProgress affiche un événement de suivi sur une seule ligne de la sortie d'erreur, réécrite à chaque
+// événement ; le dernier événement termine la ligne.
+func PrintProgress(p common.Progress) {
+	line := fmt.Sprintf("%s [%s]", p.Solver, p.Elapsed.Round(time.Millisecond))
+	if p.Nodes > 0 {
+		line += fmt.Sprintf(" noeuds : %d", p.Nodes)
+	}
+	if p.Best > 0 {
+		line += fmt.Sprintf(" meilleure valeur : %d", p.Best)
+	}
+	if p.TotalRows > 0 {
+		line += fmt.Sprintf(" lignes : %d/%d", p.Rows, p.TotalRows)
+	}
+	if p.Solver == "lll" {
+		line += fmt.Sprintf(" itération : %d k : %d échanges : %d", p.Iteration, p.K, p.Swaps)
+	}
+
+	end := ""
+	if p.Done {
+		end = "\n"
+	}
+	fmt.Fprintf(os.Stderr, "\r%-100s%s", line, end)
+}
 
-	var data []common.Objects
-	err = json.Unmarshal(fileBytes, &data)
+// LoadDataFromFile lit les objets d'un fichier, refuse ceux qui enfreignent les règles de common.ValidateObjects
+// (poids ou valeur nuls ou négatifs, exemplaires négatifs, débordements) et les trie par rapport valeur/poids.
+func LoadDataFromFile(filename string) ([]common.Objects, error) {
+	doc, err := formats.Read(filename)
 	if err != nil {
 		return nil, err
 	}
+	data := doc.Instance.Objects
+	if err := common.ValidateObjects(data); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
 
 	// Trier les objets par rapport valeur/poids décroissant
-	sort.Slice(data, func(i, j int) bool {
-		return float64(data[i].Value)/float64(data[i].Weight) > float64(data[j].Value)/float64(data[j].Weight)
-	})
+	common.SortByRatio(data)
 
 	return data, nil
 }
 
+// LoadInstanceFromFile lit une instance avec la capacité donnée (celle du fichier si elle est négative), la
+// normalise avec common.Normalize si normalize n'est pas nil, puis la valide. Les objets trop lourds ne sont
+// pas une erreur, ils ne sont jamais choisis ; toute autre infraction est renvoyée en ValidationErrors. Le
+// résultat d'un solveur sur l'instance chargée se traduit sur l'instance du fichier par Normalized.Restore.
+func LoadInstanceFromFile(filename string, capacity int, normalize *common.NormalizeOptions) (common.Normalized, error) {
+	doc, err := formats.Read(filename)
+	if err != nil {
+		return common.Normalized{}, err
+	}
+	inst := doc.Instance
+	if capacity >= 0 {
+		inst.Capacity = capacity
+	}
+
+	opts := common.NormalizeOptions{}
+	if normalize != nil {
+		opts = *normalize
+	}
+	n := common.Normalize(inst, opts)
+	if errs, ok := common.Validate(n.Instance).(common.ValidationErrors); ok {
+		if errs = errs.Without(common.TooHeavy); len(errs) > 0 {
+			return n, fmt.Errorf("%s: %w", filename, errs)
+		}
+	}
+	return n, nil
+}
+
 func SolveKnapsackWithGreedyAlgorithm(data []common.Objects, capacity int) string {
 	knapsackExecutionTime := algorithme_glouton.MeasureExecutionTime(func() {
 		selectedObjects, totalWeight, remainingWeight := algorithme_glouton.Knapsack(data, capacity)
@@ -113,40 +189,130 @@ func GenerateKeys() {
 	// fmt.Println()
 }
 
-func PerformKnapsackBenchmark(filename string, capacity int) {
-	// Charger les données depuis le fichier JSON
-	data, err := LoadDataFromFile(filename)
+/* SolveKnapsackWithSolver résout le problème du sac à dos avec n'importe quel solveur enregistré, dans la limite du contexte */
+func SolveKnapsackWithSolver(ctx context.Context, solver common.Solver, data []common.Objects, capacity int) string {
+	inst := common.Instance{Objects: data, Capacity: capacity}
+
+	res, err := solver.Solve(ctx, inst)
 	if err != nil {
-		log.Fatal("Erreur lors de la lecture du fichier :", err)
+		return fmt.Sprintf("Erreur du solveur %s : %v\n", solver.Name(), err)
+	}
+	if res.Stats.Interrupted {
+		fmt.Printf("Résolution interrompue (%v) : meilleure solution trouvée jusque-là\n", ctx.Err())
 	}
 
-	// Résoudre le problème du sac à dos avec algorithme glouton
-	fmt.Println("Résolution du problème du sac à dos avec l'algorithme glouton :")
-	resultGreedy := SolveKnapsackWithGreedyAlgorithm(data, capacity)
-	fmt.Println(resultGreedy)
+	algorithme_glouton.PrintNewBag(res.Selected(inst))
+	fmt.Printf("Le poids total du sac à dos est de %d\n", res.Weight)
+	fmt.Printf("La valeur totale du sac à dos est de %d (optimale : %t)\n", res.Value, res.Optimal)
+	if !res.Optimal && res.Bound > 0 {
+		fmt.Printf("La valeur optimale est au plus %d\n", res.Bound)
+	}
+	if res.Stats.Fixed > 0 {
+		fmt.Printf("Objets fixés par la réduction : %d\n", res.Stats.Fixed)
+	}
+	fmt.Printf("Nombre de noeuds explorés : %d\n", res.Stats.Nodes)
 
-	// Afficher la consommation mémoire
-	printMemoryUsage()
+	// Vérifier la solution indépendamment du solveur, et son certificat d'optimalité s'il en fournit un
+	if err := verification.Verify(inst, res); err != nil {
+		fmt.Printf("Vérification échouée : %v\n", err)
+	} else if res.Certificate != nil {
+		fmt.Println("Optimalité prouvée par le certificat du solveur")
+	}
 
-	// Résoudre le problème du sac à dos avec algorithme programmation dynamique
-	fmt.Println("Résolution du problème du sac à dos avec l'algorithme de programmation dynamique :")
-	resultDynamic := SolveKnapsackWithDynamicProgramming(filename, capacity)
-	fmt.Println(resultDynamic)
+	return fmt.Sprintf("Temps d'exécution total pour la résolution du problème du sac à dos avec le solveur %s : %s\n", solver.Name(), res.Stats.Duration)
+}
 
-	// Afficher la consommation mémoire
-	printMemoryUsage()
+// SolveMultipleKnapsack remplit les sacs de capacités capacities avec les objets, par le bound-and-bound de
+// multiple_knapsack.Knapsack dans la limite du contexte, et affiche le contenu de chaque sac.
+func SolveMultipleKnapsack(ctx context.Context, data []common.Objects, capacities []int) string {
+	res, err := multiple_knapsack.Knapsack(ctx, data, capacities, multiple_knapsack.Options{})
+	if err != nil {
+		return fmt.Sprintf("Erreur du sac à dos multiple : %v\n", err)
+	}
+	if res.Stats.Interrupted {
+		fmt.Printf("Résolution interrompue (%v) : meilleure solution trouvée jusque-là\n", ctx.Err())
+	}
 
-	// Résoudre le problème du sac à dos avec algorithme de recherche exhaustive
-	fmt.Println("Résolution du problème du sac à dos avec l'algorithme de recherche exhaustive :")
-	resultExhaustive := SolveKnapsackWithExhaustiveSearch(filename, capacity)
-	fmt.Println(resultExhaustive)
+	printBags(data, res.Bags, func(j int) int { return capacities[j] })
+	fmt.Printf("La valeur totale des sacs est de %d (optimale : %t)\n", res.Value, res.Optimal)
+	if !res.Optimal && res.Bound > 0 {
+		fmt.Printf("La valeur optimale est au plus %d\n", res.Bound)
+	}
+	fmt.Printf("Nombre de noeuds explorés : %d\n", res.Stats.Nodes)
 
-	// Afficher la consommation mémoire finale
-	printMemoryUsage()
+	return fmt.Sprintf("Temps d'exécution total pour la résolution du sac à dos multiple : %s\n", res.Stats.Duration)
+}
+
+// SolveBinPacking range tous les objets dans le plus petit nombre de boîtes de capacité capacity, par
+// multiple_knapsack.BinPacking dans la limite du contexte, et affiche le contenu de chaque boîte.
+func SolveBinPacking(ctx context.Context, data []common.Objects, capacity int) string {
+	res, err := multiple_knapsack.BinPacking(ctx, data, capacity, multiple_knapsack.Options{})
+	if err != nil {
+		return fmt.Sprintf("Erreur du rangement en boîtes : %v\n", err)
+	}
+	if res.Stats.Interrupted {
+		fmt.Printf("Résolution interrompue (%v) : meilleur rangement trouvé jusque-là\n", ctx.Err())
+	}
+
+	printBags(data, res.Bags, func(int) int { return capacity })
+	fmt.Printf("Nombre de boîtes utilisées : %d (optimal : %t)\n", res.Value, res.Optimal)
+	if !res.Optimal {
+		fmt.Printf("Il faut au moins %d boîtes\n", res.Bound)
+	}
+	fmt.Printf("Nombre de noeuds explorés : %d\n", res.Stats.Nodes)
+
+	return fmt.Sprintf("Temps d'exécution total pour le rangement en boîtes : %s\n", res.Stats.Duration)
+}
+
+/* printBags affiche les objets rangés dans chaque sac ou boîte */
+func printBags(data []common.Objects, bags [][]int, capacity func(j int) int) {
+	for j, bag := range bags {
+		fmt.Printf("Sac %d (capacité %d) :\n", j+1, capacity(j))
+		objects := make([]common.Objects, len(bag))
+		for k, i := range bag {
+			objects[k] = data[i]
+		}
+		algorithme_glouton.PrintNewBag(objects)
+	}
+}
+
+func PerformKnapsackBenchmark(filename string, capacity int) {
+	// Charger et valider l'instance : seuls les objets plus lourds que la capacité sont tolérés
+	loaded, err := LoadInstanceFromFile(filename, capacity, nil)
+	if err != nil {
+		log.Fatal("Instance invalide :", err)
+	}
+	data := loaded.Instance.Objects
+	common.SortByRatio(data)
+	if errs, ok := common.Validate(loaded.Instance).(common.ValidationErrors); ok {
+		fmt.Printf("Attention : %d objets plus lourds que la capacité, ils ne seront jamais choisis\n", len(errs))
+	}
+
+	// Résoudre le problème du sac à dos avec chaque solveur enregistré
+	for _, solver := range common.Solvers() {
+		if Reduce {
+			solver = reduction.Wrap(solver)
+		}
+		var before runtime.MemStats
+		runtime.ReadMemStats(&before)
+
+		fmt.Printf("Résolution du problème du sac à dos avec le solveur %s :\n", solver.Name())
+		ctx, cancel := WithProgress(context.Background()), context.CancelFunc(func() {})
+		if SolverTimeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, SolverTimeout)
+		}
+		result := SolveKnapsackWithSolver(ctx, solver, data, capacity)
+		cancel()
+		fmt.Println(result)
+
+		// Afficher la mémoire allouée par ce solveur
+		printMemoryUsage(before)
+	}
 }
 
-func printMemoryUsage() {
+/* printMemoryUsage affiche la mémoire allouée depuis la mesure before */
+func printMemoryUsage(before runtime.MemStats) {
 	var m runtime.MemStats
 	runtime.ReadMemStats(&m)
-	fmt.Printf("Consommation mémoire : %d bytes\n", m.Sys)
+	fmt.Printf("Mémoire allouée : %d octets en %d allocations\n", m.TotalAlloc-before.TotalAlloc, m.Mallocs-before.Mallocs)
 }
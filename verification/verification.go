@@ -0,0 +1,308 @@
+package verification
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"../common"
+)
+
+var (
+	ErrIndex          = errors.New("Invalid object index")
+	ErrCount          = errors.New("Invalid number of copies")
+	ErrOverweight     = errors.New("Selected objects exceed the capacity")
+	ErrValueMismatch  = errors.New("Reported value or weight does not match the selected objects")
+	ErrBound          = errors.New("Reported bound is below the reported value")
+	ErrCertificate    = errors.New("Invalid optimality certificate")
+	ErrNotCertified   = errors.New("Certificate does not prove the reported value optimal")
+	ErrNoCertificate  = errors.New("Result has no certificate")
+	ErrNegativeWeight = errors.New("Certificates require non-negative weights")
+)
+
+// Check vérifie un résultat sans faire confiance au solveur : les indices (ou les nombres d'exemplaires) doivent
+// désigner des objets de l'instance, sans doublon, et tenir dans le sac ; la valeur et le poids annoncés sont
+// recalculés, et la borne, si elle est connue, ne doit pas être inférieure à la valeur.
+func Check(inst common.Instance, res common.Result) error {
+	n := len(inst.Objects)
+	value, weight := 0, 0
+
+	if res.Counts != nil {
+		if len(res.Counts) != n {
+			return fmt.Errorf("%w: %d counts for %d objects", ErrCount, len(res.Counts), n)
+		}
+		for i, count := range res.Counts {
+			if count < 0 {
+				return fmt.Errorf("%w: %d copies of object %d", ErrCount, count, i)
+			}
+			value += count * inst.Objects[i].Value
+			weight += count * inst.Objects[i].Weight
+		}
+	} else {
+		seen := make(map[int]bool, len(res.Indices))
+		for _, i := range res.Indices {
+			if i < 0 || i >= n || seen[i] {
+				return fmt.Errorf("%w: %d", ErrIndex, i)
+			}
+			seen[i] = true
+			value += inst.Objects[i].Value
+			weight += inst.Objects[i].Weight
+		}
+	}
+
+	if weight > inst.Capacity {
+		return fmt.Errorf("%w: weight %d, capacity %d", ErrOverweight, weight, inst.Capacity)
+	}
+	if value != res.Value || weight != res.Weight {
+		return fmt.Errorf("%w: value %d (reported %d), weight %d (reported %d)", ErrValueMismatch, value, res.Value, weight, res.Weight)
+	}
+	if res.Bound != 0 && res.Bound < res.Value {
+		return fmt.Errorf("%w: bound %d, value %d", ErrBound, res.Bound, res.Value)
+	}
+	return nil
+}
+
+// Verify vérifie le résultat avec Check puis, s'il est annoncé optimal et accompagné d'un certificat, que le
+// certificat prouve l'optimalité de sa valeur. Un résultat optimal sans certificat est seulement vérifié par
+// Check ; CertifyOptimal exige le certificat.
+func Verify(inst common.Instance, res common.Result) error {
+	if err := Check(inst, res); err != nil {
+		return err
+	}
+	if res.Optimal && res.Certificate != nil {
+		return VerifyCertificate(inst, res.Value, res.Certificate)
+	}
+	return nil
+}
+
+/* CertifyOptimal vérifie le résultat et exige un certificat prouvant son optimalité */
+func CertifyOptimal(inst common.Instance, res common.Result) error {
+	if res.Certificate == nil {
+		return ErrNoCertificate
+	}
+	if err := Check(inst, res); err != nil {
+		return err
+	}
+	return VerifyCertificate(inst, res.Value, res.Certificate)
+}
+
+/* VerifyCertificate vérifie que le certificat prouve qu'aucune solution de l'instance ne dépasse value */
+func VerifyCertificate(inst common.Instance, value int, cert *common.Certificate) error {
+	if inst.Capacity < 0 {
+		return fmt.Errorf("%w: negative capacity %d", ErrCertificate, inst.Capacity)
+	}
+	for i, obj := range inst.Objects {
+		if obj.Weight < 0 {
+			return fmt.Errorf("%w: object %d", ErrNegativeWeight, i)
+		}
+	}
+
+	switch cert.Kind {
+	case common.DualBound:
+		return verifyDual(inst, value, cert)
+	case common.SearchTree:
+		return verifyTree(inst, value, cert)
+	case common.ValueTable:
+		return verifyTable(inst, value, cert)
+	}
+	return fmt.Errorf("%w: unknown kind %d", ErrCertificate, cert.Kind)
+}
+
+// verifyDual calcule en précision arbitraire Den·borne = Num·C + somme(max(0, Den·p_j - Num·w_j)) et vérifie
+// que la borne est strictement inférieure à value + 1.
+func verifyDual(inst common.Instance, value int, cert *common.Certificate) error {
+	if cert.Num < 0 || cert.Den <= 0 {
+		return fmt.Errorf("%w: multiplier %d/%d", ErrCertificate, cert.Num, cert.Den)
+	}
+	num, den := big.NewInt(int64(cert.Num)), big.NewInt(int64(cert.Den))
+
+	bound := new(big.Int).Mul(num, big.NewInt(int64(inst.Capacity)))
+	gain, cost := new(big.Int), new(big.Int)
+	for _, obj := range inst.Objects {
+		gain.Mul(den, big.NewInt(int64(obj.Value)))
+		gain.Sub(gain, cost.Mul(num, big.NewInt(int64(obj.Weight))))
+		if gain.Sign() > 0 {
+			bound.Add(bound, gain)
+		}
+	}
+
+	limit := new(big.Int).Mul(den, big.NewInt(int64(value)+1))
+	if bound.Cmp(limit) >= 0 {
+		bound.Quo(bound, den)
+		return fmt.Errorf("%w: Lagrangian bound %s, value %d", ErrNotCertified, bound, value)
+	}
+	return nil
+}
+
+// verifyTable refait la programmation dynamique sur une seule ligne, f(c) étant la meilleure valeur de capacité
+// c, compare son empreinte à celle du certificat et vérifie que f(C) est la valeur trouvée.
+func verifyTable(inst common.Instance, value int, cert *common.Certificate) error {
+	row := make([]int, inst.Capacity+1)
+	for _, obj := range inst.Objects {
+		if obj.Value <= 0 || obj.Weight > inst.Capacity {
+			continue
+		}
+		for c := inst.Capacity; c >= obj.Weight; c-- {
+			if v := row[c-obj.Weight] + obj.Value; v > row[c] {
+				row[c] = v
+			}
+		}
+	}
+
+	if !bytes.Equal(common.RowDigest(row), cert.Digest) {
+		return fmt.Errorf("%w: value table digest does not match", ErrCertificate)
+	}
+	if row[inst.Capacity] != value {
+		return fmt.Errorf("%w: optimum %d, value %d", ErrNotCertified, row[inst.Capacity], value)
+	}
+	return nil
+}
+
+// verifyTree vérifie un arbre de séparation : l'ordre doit contenir tous les objets de valeur positive et
+// seulement eux, triés par rapport décroissant (condition des bornes) ; les feuilles doivent former un code
+// préfixe complet (aucune n'est préfixe d'une autre et la somme des 2^-profondeur vaut 1, donc toutes les
+// affectations sont couvertes) et chaque feuille doit être irréalisable ou bornée par value.
+func verifyTree(inst common.Instance, value int, cert *common.Certificate) error {
+	n := len(cert.Order)
+	inOrder := make(map[int]bool, n)
+	for k, i := range cert.Order {
+		if i < 0 || i >= len(inst.Objects) || inOrder[i] || inst.Objects[i].Value <= 0 {
+			return fmt.Errorf("%w: order contains index %d", ErrCertificate, i)
+		}
+		inOrder[i] = true
+		if k > 0 && common.BetterRatio(inst.Objects[i], inst.Objects[cert.Order[k-1]]) {
+			return fmt.Errorf("%w: order is not sorted by ratio at position %d", ErrCertificate, k)
+		}
+	}
+	for i, obj := range inst.Objects {
+		if !inOrder[i] && obj.Value > 0 {
+			return fmt.Errorf("%w: object %d of positive value is missing from the order", ErrCertificate, i)
+		}
+	}
+
+	paths := make([][]bool, len(cert.Leaves))
+	for l, leaf := range cert.Leaves {
+		if leaf.Depth < 0 || leaf.Depth > n {
+			return fmt.Errorf("%w: leaf %d has depth %d", ErrCertificate, l, leaf.Depth)
+		}
+		paths[l] = make([]bool, leaf.Depth)
+		for t, k := range leaf.Taken {
+			if k < 0 || k >= leaf.Depth || (t > 0 && k <= leaf.Taken[t-1]) {
+				return fmt.Errorf("%w: leaf %d has invalid position %d", ErrCertificate, l, k)
+			}
+			paths[l][k] = true
+		}
+	}
+	if err := checkComplete(paths); err != nil {
+		return err
+	}
+
+	weights := make([]int, n)
+	values := make([]int, n)
+	for k, i := range cert.Order {
+		weights[k], values[k] = inst.Objects[i].Weight, inst.Objects[i].Value
+	}
+	for l, path := range paths {
+		weight, prefix := 0, 0
+		for k, taken := range path {
+			if taken {
+				weight += weights[k]
+				prefix += values[k]
+			}
+		}
+		if weight > inst.Capacity {
+			continue
+		}
+		if bound := upperBound(weights[len(path):], values[len(path):], inst.Capacity-weight); prefix+bound > value {
+			return fmt.Errorf("%w: leaf %d has bound %d, value %d", ErrNotCertified, l, prefix+bound, value)
+		}
+	}
+	return nil
+}
+
+/* checkComplete vérifie que les chemins forment un code préfixe complet de l'arbre binaire */
+func checkComplete(paths [][]bool) error {
+	if len(paths) == 0 {
+		return fmt.Errorf("%w: no leaves", ErrCertificate)
+	}
+
+	// Dans l'ordre lexicographique, un chemin qui est préfixe d'un autre est aussi préfixe de son successeur
+	sorted := append([][]bool(nil), paths...)
+	sort.Slice(sorted, func(a, b int) bool { return less(sorted[a], sorted[b]) })
+	depth := 0
+	for l, path := range sorted {
+		if l > 0 && isPrefix(sorted[l-1], path) {
+			return fmt.Errorf("%w: a leaf is an ancestor of another", ErrCertificate)
+		}
+		if len(path) > depth {
+			depth = len(path)
+		}
+	}
+
+	// Inégalité de Kraft : somme des 2^(depth - profondeur) = 2^depth
+	sum, term := new(big.Int), new(big.Int)
+	for _, path := range paths {
+		sum.Add(sum, term.Lsh(big.NewInt(1), uint(depth-len(path))))
+	}
+	if sum.Cmp(term.Lsh(big.NewInt(1), uint(depth))) != 0 {
+		return fmt.Errorf("%w: leaves do not cover every assignment", ErrCertificate)
+	}
+	return nil
+}
+
+func less(a, b []bool) bool {
+	for k := 0; k < len(a) && k < len(b); k++ {
+		if a[k] != b[k] {
+			return b[k]
+		}
+	}
+	return len(a) < len(b)
+}
+
+func isPrefix(a, b []bool) bool {
+	if len(a) > len(b) {
+		return false
+	}
+	for k := range a {
+		if a[k] != b[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// upperBound calcule la borne U2 de Martello-Toth (au plus la borne de Dantzig) des objets restants, triés par
+// rapport décroissant, pour une capacité restante donnée.
+func upperBound(weights, values []int, capacity int) int {
+	n := len(weights)
+	value, b := 0, 0
+	for b < n && weights[b] <= capacity {
+		capacity -= weights[b]
+		value += values[b]
+		b++
+	}
+	if b == n {
+		return value
+	}
+
+	// U0 : l'objet critique est exclu
+	u0 := value
+	if b+1 < n {
+		u0 += capacity * values[b+1] / weights[b+1]
+	}
+	if b == 0 {
+		return u0
+	}
+
+	// U1 : l'objet critique est inclus, une fraction de l'objet précédent retirée
+	if weights[b-1] == 0 {
+		return value + capacity*values[b]/weights[b]
+	}
+	u1 := value + values[b] - ((weights[b]-capacity)*values[b-1]+weights[b-1]-1)/weights[b-1]
+	if u1 > u0 {
+		return u1
+	}
+	return u0
+}
@@ -0,0 +1,201 @@
+package verification_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	_ "../algo_prog_dynamique"
+	_ "../branch_and_bound"
+	"../common"
+	"../verification"
+)
+
+// hard est une instance dont la borne duale vaut 11 pour un optimum de 10 : les solveurs doivent fournir un
+// autre certificat que DualBound.
+var hard = common.Instance{Objects: []common.Objects{{Weight: 6, Value: 7}, {Weight: 5, Value: 5}, {Weight: 5, Value: 5}}, Capacity: 10}
+
+/* easy est une instance dont la borne duale est atteinte */
+var easy = common.Instance{Objects: []common.Objects{{Weight: 4, Value: 8}, {Weight: 6, Value: 6}, {Weight: 3, Value: 1}}, Capacity: 10}
+
+func solve(t *testing.T, name string, inst common.Instance) common.Result {
+	t.Helper()
+	solver, err := common.Lookup(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := solver.Solve(context.Background(), inst)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	return res
+}
+
+func TestCertificateKinds(t *testing.T) {
+	tests := []struct {
+		solver string
+		inst   common.Instance
+		kind   common.CertificateKind
+	}{
+		{"dp", hard, common.ValueTable},
+		{"dp_hirschberg", hard, common.ValueTable},
+		{"branch_and_bound", hard, common.SearchTree},
+		{"dp", easy, common.DualBound},
+	}
+
+	for _, test := range tests {
+		res := solve(t, test.solver, test.inst)
+		if res.Certificate == nil || res.Certificate.Kind != test.kind {
+			t.Errorf("%s: expected certificate kind %d, got %+v", test.solver, test.kind, res.Certificate)
+			continue
+		}
+		if err := verification.CertifyOptimal(test.inst, res); err != nil {
+			t.Errorf("%s: %v", test.solver, err)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		res  common.Result
+		err  error
+	}{
+		{"valid", common.Result{Indices: []int{1, 2}, Value: 10, Weight: 10, Bound: 11}, nil},
+		{"index out of range", common.Result{Indices: []int{3}}, verification.ErrIndex},
+		{"duplicate index", common.Result{Indices: []int{1, 1}, Value: 10, Weight: 10}, verification.ErrIndex},
+		{"overweight", common.Result{Indices: []int{0, 1}, Value: 12, Weight: 11}, verification.ErrOverweight},
+		{"wrong value", common.Result{Indices: []int{1, 2}, Value: 11, Weight: 10}, verification.ErrValueMismatch},
+		{"bound below value", common.Result{Indices: []int{1, 2}, Value: 10, Weight: 10, Bound: 9}, verification.ErrBound},
+		{"negative count", common.Result{Counts: []int{0, -1, 0}}, verification.ErrCount},
+	}
+
+	for _, test := range tests {
+		if err := verification.Check(hard, test.res); !errors.Is(err, test.err) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.err, err)
+		}
+	}
+}
+
+/* cloneTree copie un certificat en arbre pour pouvoir l'altérer */
+func cloneTree(cert *common.Certificate) *common.Certificate {
+	c := *cert
+	c.Order = append([]int(nil), cert.Order...)
+	c.Leaves = make([]common.Leaf, len(cert.Leaves))
+	for l, leaf := range cert.Leaves {
+		c.Leaves[l] = common.Leaf{Depth: leaf.Depth, Taken: append([]int(nil), leaf.Taken...)}
+	}
+	return &c
+}
+
+func TestTamperedSearchTree(t *testing.T) {
+	res := solve(t, "branch_and_bound", hard)
+	tree := res.Certificate
+
+	tests := []struct {
+		name   string
+		tamper func(c *common.Certificate)
+		err    error
+	}{
+		{"unchanged", func(c *common.Certificate) {}, nil},
+		{"missing leaf", func(c *common.Certificate) { c.Leaves = c.Leaves[1:] }, verification.ErrCertificate},
+		{"duplicate leaf", func(c *common.Certificate) { c.Leaves = append(c.Leaves, c.Leaves[0]) }, verification.ErrCertificate},
+		{"no leaves", func(c *common.Certificate) { c.Leaves = nil }, verification.ErrCertificate},
+		{"root only", func(c *common.Certificate) { c.Leaves = []common.Leaf{{}} }, verification.ErrNotCertified},
+		{"leaf too deep", func(c *common.Certificate) { c.Leaves[0].Depth = len(c.Order) + 1 }, verification.ErrCertificate},
+		{"taken outside leaf", func(c *common.Certificate) { c.Leaves[0].Taken = []int{c.Leaves[0].Depth} }, verification.ErrCertificate},
+		{"unsorted order", func(c *common.Certificate) { c.Order[0], c.Order[1] = c.Order[1], c.Order[0] }, verification.ErrCertificate},
+		{"order misses an object", func(c *common.Certificate) { c.Order = c.Order[:len(c.Order)-1] }, verification.ErrCertificate},
+		{"order repeats an object", func(c *common.Certificate) { c.Order[1] = c.Order[0] }, verification.ErrCertificate},
+	}
+
+	for _, test := range tests {
+		cert := cloneTree(tree)
+		test.tamper(cert)
+		if err := verification.VerifyCertificate(hard, res.Value, cert); !errors.Is(err, test.err) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.err, err)
+		}
+	}
+
+	// L'arbre ne prouve pas une valeur inférieure à l'optimum
+	if err := verification.VerifyCertificate(hard, res.Value-1, tree); !errors.Is(err, verification.ErrNotCertified) {
+		t.Errorf("Expected ErrNotCertified for a lower value, got %v", err)
+	}
+}
+
+func TestWrongMultiplier(t *testing.T) {
+	res := solve(t, "dp", easy)
+	cert := *res.Certificate
+	if err := verification.VerifyCertificate(easy, res.Value, &cert); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		num, den int
+		err      error
+	}{
+		{"zero multiplier", 0, 1, verification.ErrNotCertified},
+		{"large multiplier", 10, 1, verification.ErrNotCertified},
+		{"negative multiplier", -1, 1, verification.ErrCertificate},
+		{"zero denominator", 1, 0, verification.ErrCertificate},
+	}
+	for _, test := range tests {
+		cert.Num, cert.Den = test.num, test.den
+		if err := verification.VerifyCertificate(easy, res.Value, &cert); !errors.Is(err, test.err) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.err, err)
+		}
+	}
+}
+
+func TestTamperedValueTable(t *testing.T) {
+	res := solve(t, "dp", hard)
+
+	if err := verification.VerifyCertificate(hard, res.Value-1, res.Certificate); !errors.Is(err, verification.ErrNotCertified) {
+		t.Errorf("Expected ErrNotCertified for a lower value, got %v", err)
+	}
+
+	cert := *res.Certificate
+	cert.Digest = append([]byte(nil), cert.Digest...)
+	cert.Digest[0] ^= 1
+	if err := verification.VerifyCertificate(hard, res.Value, &cert); !errors.Is(err, verification.ErrCertificate) {
+		t.Errorf("Expected ErrCertificate for a tampered digest, got %v", err)
+	}
+
+	// Le certificat d'une instance ne vaut pas pour une autre
+	other := common.Instance{Objects: hard.Objects, Capacity: hard.Capacity + 1}
+	if err := verification.VerifyCertificate(other, res.Value, res.Certificate); !errors.Is(err, verification.ErrCertificate) {
+		t.Errorf("Expected ErrCertificate for another capacity, got %v", err)
+	}
+}
+
+func TestNegativeWeights(t *testing.T) {
+	inst := common.Instance{Objects: []common.Objects{{Weight: -1, Value: 1}}, Capacity: 1}
+	cert := &common.Certificate{Kind: common.DualBound, Num: 0, Den: 1}
+	if err := verification.VerifyCertificate(inst, 1, cert); !errors.Is(err, verification.ErrNegativeWeight) {
+		t.Errorf("Expected ErrNegativeWeight, got %v", err)
+	}
+}
+
+func TestDualCertificateLargeValues(t *testing.T) {
+	// Num·C et Den·p_j dépassent un int : la borne calculée en int passait sous la valeur 0
+	inst := common.Instance{Objects: []common.Objects{
+		{Weight: 4428987731, Value: 36091728802},
+		{Weight: 31785841182, Value: 46739418657},
+		{Weight: 5532738258, Value: 31522573226},
+	}, Capacity: 40686190622}
+	if cert := common.DualCertificate(inst, 0); cert != nil {
+		t.Errorf("Certificate %+v issued for value 0, Lagrangian bound far above", cert)
+	}
+
+	// Instance où la borne de Dantzig est atteinte, multipliée par 2^33 : le certificat reste valide
+	k := 1 << 33
+	inst = common.Instance{Objects: []common.Objects{{Weight: 2 * k, Value: 4 * k}, {Weight: 3 * k, Value: 3 * k}}, Capacity: 2 * k}
+	cert := common.DualCertificate(inst, 4*k)
+	if cert == nil {
+		t.Fatal("No certificate for the optimal value")
+	}
+	if err := verification.VerifyCertificate(inst, 4*k, cert); err != nil {
+		t.Error(err)
+	}
+}